@@ -0,0 +1,85 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// allowDirectiveRegex matches an inline "security-hooks:allow" comment, or
+// its "security-hooks:ignore-secret" alias, with an optional
+// "=ruleID,ruleID2" suffix restricting which rule IDs it covers. It's
+// recognized anywhere on the line, so it works whether the surrounding
+// comment syntax is "#", "//", "--", or anything else.
+var allowDirectiveRegex = regexp.MustCompile(`security-hooks:(?:allow|ignore-secret)(?:=([A-Za-z0-9,_-]+))?\b`)
+
+// parseAllowDirective looks for a security-hooks:allow directive in line. ok
+// is true whenever the directive is present at all; ids is nil when it's
+// present without a "=ruleID,..." list, meaning "allow everything on this
+// line", and otherwise holds the set of rule IDs it allows.
+func parseAllowDirective(line string) (ids map[string]struct{}, ok bool) {
+	m := allowDirectiveRegex.FindStringSubmatch(line)
+	if m == nil {
+		return nil, false
+	}
+	if m[1] == "" {
+		return nil, true
+	}
+
+	ids = make(map[string]struct{})
+	for _, id := range strings.Split(m[1], ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids[id] = struct{}{}
+		}
+	}
+	return ids, true
+}
+
+// lineAllows reports whether a finding for ruleID should be suppressed
+// because thisLine or the immediately preceding line carries a
+// security-hooks:allow directive covering it.
+func lineAllows(ruleID, thisLine, prevLine string) bool {
+	for _, line := range [2]string{thisLine, prevLine} {
+		ids, ok := parseAllowDirective(line)
+		if !ok {
+			continue
+		}
+		if ids == nil {
+			return true
+		}
+		if _, listed := ids[ruleID]; listed {
+			return true
+		}
+	}
+	return false
+}
+
+// filterAllowDirectives drops any finding whose source line (or the line
+// immediately before it) carries a security-hooks:allow directive covering
+// its RuleID. lineOffset translates a finding's Line, which may have been
+// shifted to report position within a larger file (see checkContentForSecrets),
+// back to an index into content.
+func filterAllowDirectives(findings []Finding, content string, lineOffset int) []Finding {
+	if len(findings) == 0 {
+		return findings
+	}
+
+	lines := strings.Split(content, "\n")
+	surviving := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		idx := f.Line - lineOffset - 1
+		if idx < 0 || idx >= len(lines) {
+			surviving = append(surviving, f)
+			continue
+		}
+
+		var prev string
+		if idx > 0 {
+			prev = lines[idx-1]
+		}
+		if lineAllows(f.RuleID, lines[idx], prev) {
+			continue
+		}
+		surviving = append(surviving, f)
+	}
+	return surviving
+}
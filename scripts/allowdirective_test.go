@@ -0,0 +1,130 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestParseAllowDirective
+// ---------------------------------------------------------------------------
+
+func Test_ParseAllowDirective_Cases(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		wantOK  bool
+		wantIDs []string // nil means "allow everything" (ids == nil)
+	}{
+		{name: "no directive", line: "api_key = 'sk-ant-xxxx'", wantOK: false},
+		{name: "bare directive", line: "api_key = 'sk-ant-xxxx' # security-hooks:allow", wantOK: true, wantIDs: nil},
+		{name: "single rule ID", line: "// security-hooks:allow=anthropic-api-key", wantOK: true, wantIDs: []string{"anthropic-api-key"}},
+		{name: "multiple rule IDs", line: "# security-hooks:allow=aws,github-pat", wantOK: true, wantIDs: []string{"aws", "github-pat"}},
+		{name: "ignore-secret alias, bare", line: "api_key = 'sk-ant-xxxx' # security-hooks:ignore-secret", wantOK: true, wantIDs: nil},
+		{name: "ignore-secret alias, scoped", line: "// security-hooks:ignore-secret=anthropic-api-key", wantOK: true, wantIDs: []string{"anthropic-api-key"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, ok := parseAllowDirective(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("parseAllowDirective(%q) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			}
+			if tt.wantIDs == nil {
+				if ids != nil {
+					t.Errorf("parseAllowDirective(%q) ids = %v, want nil", tt.line, ids)
+				}
+				return
+			}
+			for _, id := range tt.wantIDs {
+				if _, ok := ids[id]; !ok {
+					t.Errorf("parseAllowDirective(%q) ids = %v, missing %q", tt.line, ids, id)
+				}
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestLineAllows
+// ---------------------------------------------------------------------------
+
+func Test_LineAllows_Cases(t *testing.T) {
+	tests := []struct {
+		name     string
+		ruleID   string
+		thisLine string
+		prevLine string
+		want     bool
+	}{
+		{
+			name:     "bare allow on the same line suppresses any rule",
+			ruleID:   "anthropic-api-key",
+			thisLine: "key = 'sk-ant-xxxx' # security-hooks:allow",
+			want:     true,
+		},
+		{
+			name:     "bare allow on the preceding line suppresses any rule",
+			ruleID:   "anthropic-api-key",
+			thisLine: "key = 'sk-ant-xxxx'",
+			prevLine: "// security-hooks:allow",
+			want:     true,
+		},
+		{
+			name:     "scoped allow covers only the listed rule ID",
+			ruleID:   "anthropic-api-key",
+			thisLine: "key = 'sk-ant-xxxx' # security-hooks:allow=anthropic-api-key",
+			want:     true,
+		},
+		{
+			name:     "scoped allow does not cover an unlisted rule ID",
+			ruleID:   "github-pat",
+			thisLine: "key = 'sk-ant-xxxx' # security-hooks:allow=anthropic-api-key",
+			want:     false,
+		},
+		{
+			name:     "no directive present",
+			ruleID:   "anthropic-api-key",
+			thisLine: "key = 'sk-ant-xxxx'",
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineAllows(tt.ruleID, tt.thisLine, tt.prevLine); got != tt.want {
+				t.Errorf("lineAllows(%q, %q, %q) = %v, want %v", tt.ruleID, tt.thisLine, tt.prevLine, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestCheckContentForSecrets allow-directive integration
+// ---------------------------------------------------------------------------
+
+func Test_CheckFileForSecrets_InlineAllowSuppressesSameLineMatch(t *testing.T) {
+	content := "key = 'sk-ant-" + strings.Repeat("x", 30) + "' # security-hooks:allow\n"
+	patternIssues, _ := CheckFileForSecrets("test.py", content, map[string]CompiledEnvPattern{})
+	assertNotContainsSubstring(t, patternIssues, "Anthropic")
+}
+
+func Test_CheckFileForSecrets_InlineAllowSuppressesPrecedingLineMatch(t *testing.T) {
+	content := "# security-hooks:allow\nkey = 'sk-ant-" + strings.Repeat("x", 30) + "'\n"
+	patternIssues, _ := CheckFileForSecrets("test.py", content, map[string]CompiledEnvPattern{})
+	assertNotContainsSubstring(t, patternIssues, "Anthropic")
+}
+
+func Test_CheckFileForSecrets_IgnoreSecretAliasSuppressesSameLineMatch(t *testing.T) {
+	content := "key = 'sk-ant-" + strings.Repeat("x", 30) + "' # security-hooks:ignore-secret\n"
+	patternIssues, _ := CheckFileForSecrets("test.py", content, map[string]CompiledEnvPattern{})
+	assertNotContainsSubstring(t, patternIssues, "Anthropic")
+}
+
+func Test_CheckFileForSecrets_ScopedAllowLeavesOtherRulesIntact(t *testing.T) {
+	content := "key1 = 'sk-ant-" + strings.Repeat("x", 30) + "' # security-hooks:allow=github-pat\n" +
+		"key2 = 'ghp_" + strings.Repeat("A", 36) + "' # security-hooks:allow=github-pat\n"
+	patternIssues, _ := CheckFileForSecrets("test.py", content, map[string]CompiledEnvPattern{})
+	assertContainsSubstring(t, patternIssues, "Anthropic")
+	assertNotContainsSubstring(t, patternIssues, "GitHub")
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// baselineFileName is the default name of the baseline/allowlist file,
+// overridable via the SECRETS_BASELINE environment variable.
+const baselineFileName = ".secrets-baseline.json"
+
+// BaselineEntry is one suppressed finding recorded in the baseline file.
+type BaselineEntry struct {
+	FilePath string `json:"file_path"`
+	RuleID   string `json:"rule_id"`
+	LineHash string `json:"line_hash"`
+	IsSecret *bool  `json:"is_secret,omitempty"`
+}
+
+// Baseline is the parsed contents of a .secrets-baseline.json file, indexed
+// for fast lookup by (file_path, rule_id, line_hash).
+type Baseline struct {
+	Entries []BaselineEntry
+	index   map[string]*bool
+	// suppressed counts findings Suppresses has silently skipped, accessed
+	// via SuppressedCount for a post-scan summary. Updated atomically since
+	// scanStagedFilesParallel calls Suppresses from multiple worker
+	// goroutines against the same Baseline.
+	suppressed int64
+}
+
+// baselineKey builds the lookup key shared by Baseline.Suppresses and the
+// entries recorded by BuildBaselineEntries.
+func baselineKey(filePath, ruleID, lineHash string) string {
+	return filePath + "\x00" + ruleID + "\x00" + lineHash
+}
+
+// BaselinePath returns the baseline file path for projectRoot, honoring the
+// SECRETS_BASELINE override.
+func BaselinePath(projectRoot string) string {
+	if p := os.Getenv("SECRETS_BASELINE"); p != "" {
+		return p
+	}
+	return filepath.Join(projectRoot, baselineFileName)
+}
+
+// LoadBaseline reads and indexes the baseline file at path. A missing file
+// is not an error; it yields an empty baseline, since most repos start with
+// none.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Baseline{index: make(map[string]*bool)}, nil
+		}
+		return nil, fmt.Errorf("failed to read baseline %q: %w", path, err)
+	}
+
+	var entries []BaselineEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid baseline file %q: %w", path, err)
+	}
+
+	b := &Baseline{Entries: entries, index: make(map[string]*bool, len(entries))}
+	for _, e := range entries {
+		b.index[baselineKey(e.FilePath, e.RuleID, e.LineHash)] = e.IsSecret
+	}
+	return b, nil
+}
+
+// Suppresses reports whether f is recorded in the baseline as already
+// audited, so the hook should not block the commit on its account. An
+// entry explicitly audited as a real secret (is_secret: true) is never
+// suppressed, even once baselined, so --audit stays meaningful: only
+// entries with no verdict yet or marked is_secret: false are silenced.
+// Each suppression is counted; see SuppressedCount.
+func (b *Baseline) Suppresses(f Finding) bool {
+	if b == nil || f.LineHash == "" {
+		return false
+	}
+	isSecret, ok := b.index[baselineKey(f.FilePath, f.RuleID, f.LineHash)]
+	if !ok {
+		return false
+	}
+	if isSecret != nil && *isSecret {
+		return false
+	}
+	atomic.AddInt64(&b.suppressed, 1)
+	return true
+}
+
+// SuppressedCount returns how many findings this baseline has suppressed
+// via Suppresses so far, safe to call while a concurrent scan is still
+// calling Suppresses. Used to print a summary line after a scan completes,
+// so baseline-accepted findings are silently skipped but not silently
+// forgotten.
+func (b *Baseline) SuppressedCount() int64 {
+	if b == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&b.suppressed)
+}
+
+// filterSuppressed drops any finding recorded in baseline, returning
+// findings unchanged if baseline is nil or empty.
+func filterSuppressed(findings []Finding, baseline *Baseline) []Finding {
+	if baseline == nil || len(baseline.index) == 0 || len(findings) == 0 {
+		return findings
+	}
+
+	surviving := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		if baseline.Suppresses(f) {
+			continue
+		}
+		surviving = append(surviving, f)
+	}
+	return surviving
+}
+
+// LineHash computes a finding's baseline key component: everything up to
+// and including the matched secret at [start, end), SHA-256 hex-encoded.
+// Text after the secret is dropped before hashing, so an edit later on the
+// line (e.g. a trailing comment) leaves the hash unchanged; editing the
+// secret itself changes it.
+func LineHash(line string, start, end int) string {
+	sum := sha256.Sum256([]byte(line[:end]))
+	return hex.EncodeToString(sum[:])
+}
+
+// SecretHash computes a SHA-256 hex digest of the raw matched secret text,
+// for callers that need to pin an exact value (e.g. a .security-hooks.json
+// allowlist entry's "secret" field) rather than a whole line via LineHash.
+func SecretHash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// extractLine returns line number lineNum (1-based) from content, and
+// whether content actually has that many lines.
+func extractLine(content string, lineNum int) (string, bool) {
+	if lineNum < 1 {
+		return "", false
+	}
+	lines := strings.Split(content, "\n")
+	if lineNum > len(lines) {
+		return "", false
+	}
+	return lines[lineNum-1], true
+}
+
+// BuildBaselineEntries converts findings into baseline entries ready to
+// write to disk, appending to existing rather than replacing it: an entry
+// already in existing is kept even if findings doesn't reproduce it (e.g.
+// this run only scanned a subset of staged files), and any entry also
+// matched by findings carries over its existing is_secret verdict so
+// re-running --update-baseline doesn't discard prior --audit verdicts.
+func BuildBaselineEntries(findings []Finding, existing *Baseline) []BaselineEntry {
+	byKey := make(map[string]BaselineEntry)
+	if existing != nil {
+		for _, e := range existing.Entries {
+			byKey[baselineKey(e.FilePath, e.RuleID, e.LineHash)] = e
+		}
+	}
+
+	for _, f := range findings {
+		if f.LineHash == "" {
+			continue
+		}
+		key := baselineKey(f.FilePath, f.RuleID, f.LineHash)
+		entry := BaselineEntry{FilePath: f.FilePath, RuleID: f.RuleID, LineHash: f.LineHash}
+		if prior, ok := byKey[key]; ok {
+			entry.IsSecret = prior.IsSecret
+		}
+		byKey[key] = entry
+	}
+
+	entries := make([]BaselineEntry, 0, len(byKey))
+	for _, e := range byKey {
+		entries = append(entries, e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].FilePath != entries[j].FilePath {
+			return entries[i].FilePath < entries[j].FilePath
+		}
+		if entries[i].RuleID != entries[j].RuleID {
+			return entries[i].RuleID < entries[j].RuleID
+		}
+		return entries[i].LineHash < entries[j].LineHash
+	})
+
+	return entries
+}
+
+// WriteBaseline marshals entries as indented JSON and writes them to path.
+func WriteBaseline(path string, entries []BaselineEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write baseline to %q: %w", path, err)
+	}
+	return nil
+}
+
+// AuditBaseline prompts on out for every entry still missing an is_secret
+// verdict, reads a y/n answer from in for each, then writes the updated
+// entries back to path. Used by the hook's --audit mode.
+func AuditBaseline(path string, entries []BaselineEntry, in *bufio.Scanner, out *os.File) ([]BaselineEntry, error) {
+	for i := range entries {
+		if entries[i].IsSecret != nil {
+			continue
+		}
+
+		hashPreview := entries[i].LineHash
+		if len(hashPreview) > 12 {
+			hashPreview = hashPreview[:12]
+		}
+		fmt.Fprintf(out, "%s (rule %s, line_hash %s) - is this a real secret? [y/n] ",
+			entries[i].FilePath, entries[i].RuleID, hashPreview)
+
+		if !in.Scan() {
+			break
+		}
+		answer := strings.ToLower(strings.TrimSpace(in.Text()))
+		isSecret := answer == "y" || answer == "yes"
+		entries[i].IsSecret = &isSecret
+	}
+
+	if err := WriteBaseline(path, entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
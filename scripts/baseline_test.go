@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_LoadBaseline_MissingFileIsEmpty(t *testing.T) {
+	b, err := LoadBaseline(filepath.Join(t.TempDir(), ".secrets-baseline.json"))
+	if err != nil {
+		t.Fatalf("LoadBaseline() error = %v, want nil", err)
+	}
+	if len(b.Entries) != 0 {
+		t.Errorf("LoadBaseline() on missing file = %d entries, want 0", len(b.Entries))
+	}
+	if b.Suppresses(Finding{FilePath: "a.py", RuleID: "x", LineHash: "h"}) {
+		t.Error("empty baseline should not suppress anything")
+	}
+}
+
+func Test_LoadBaseline_IndexesExistingEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".secrets-baseline.json")
+	entries := []BaselineEntry{{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: "deadbeef"}}
+	if err := WriteBaseline(path, entries); err != nil {
+		t.Fatalf("WriteBaseline() error = %v", err)
+	}
+
+	b, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline() error = %v", err)
+	}
+
+	f := Finding{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: "deadbeef"}
+	if !b.Suppresses(f) {
+		t.Error("Suppresses() = false, want true for a finding matching an existing entry")
+	}
+	if b.Suppresses(Finding{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: "other"}) {
+		t.Error("Suppresses() = true, want false for a finding with a different line_hash")
+	}
+}
+
+func Test_LoadBaseline_AuditedRealSecretIsNotSuppressed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".secrets-baseline.json")
+	isSecret := true
+	entries := []BaselineEntry{{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: "deadbeef", IsSecret: &isSecret}}
+	if err := WriteBaseline(path, entries); err != nil {
+		t.Fatalf("WriteBaseline() error = %v", err)
+	}
+
+	b, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline() error = %v", err)
+	}
+
+	f := Finding{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: "deadbeef"}
+	if b.Suppresses(f) {
+		t.Error("Suppresses() = true, want false for an entry audited as is_secret: true")
+	}
+}
+
+func Test_LoadBaseline_AuditedFalsePositiveIsSuppressed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".secrets-baseline.json")
+	isSecret := false
+	entries := []BaselineEntry{{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: "deadbeef", IsSecret: &isSecret}}
+	if err := WriteBaseline(path, entries); err != nil {
+		t.Fatalf("WriteBaseline() error = %v", err)
+	}
+
+	b, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline() error = %v", err)
+	}
+
+	f := Finding{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: "deadbeef"}
+	if !b.Suppresses(f) {
+		t.Error("Suppresses() = false, want true for an entry audited as is_secret: false")
+	}
+}
+
+func Test_LineHash_StableAcrossUnrelatedEdits(t *testing.T) {
+	line := "api_key = 'sk-ant-secretvalue' # TODO rotate"
+	start := strings.Index(line, "sk-ant-secretvalue")
+	end := start + len("sk-ant-secretvalue")
+
+	original := LineHash(line, start, end)
+
+	edited := "api_key = 'sk-ant-secretvalue' # TODO rotate soon"
+	editedStart := strings.Index(edited, "sk-ant-secretvalue")
+	editedEnd := editedStart + len("sk-ant-secretvalue")
+
+	if got := LineHash(edited, editedStart, editedEnd); got != original {
+		t.Errorf("LineHash() = %q after an unrelated comment edit, want unchanged %q", got, original)
+	}
+}
+
+func Test_LineHash_ChangesWithSecretValue(t *testing.T) {
+	line := "api_key = 'sk-ant-secretvalue'"
+	start := strings.Index(line, "sk-ant-secretvalue")
+	end := start + len("sk-ant-secretvalue")
+	original := LineHash(line, start, end)
+
+	rotated := "api_key = 'sk-ant-differentvalue'"
+	rotatedStart := strings.Index(rotated, "sk-ant-differentvalue")
+	rotatedEnd := rotatedStart + len("sk-ant-differentvalue")
+
+	if got := LineHash(rotated, rotatedStart, rotatedEnd); got == original {
+		t.Error("LineHash() unchanged after the secret value itself changed, want a different hash")
+	}
+}
+
+func Test_BuildBaselineEntries_DedupsAndCarriesOverVerdict(t *testing.T) {
+	isSecret := true
+	existing := &Baseline{Entries: []BaselineEntry{
+		{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: "hash1", IsSecret: &isSecret},
+	}}
+
+	findings := []Finding{
+		{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: "hash1"},
+		{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: "hash1"}, // duplicate
+		{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: ""},      // unhashable, dropped
+		{FilePath: "other.py", RuleID: "github-pat", LineHash: "hash2"},
+	}
+
+	entries := BuildBaselineEntries(findings, existing)
+	if len(entries) != 2 {
+		t.Fatalf("BuildBaselineEntries() = %d entries, want 2", len(entries))
+	}
+	if entries[0].IsSecret == nil || !*entries[0].IsSecret {
+		t.Error("BuildBaselineEntries() did not carry over the existing is_secret verdict")
+	}
+	if entries[1].IsSecret != nil {
+		t.Error("BuildBaselineEntries() invented an is_secret verdict for a new finding")
+	}
+}
+
+func Test_BuildBaselineEntries_PreservesExistingEntriesNotInCurrentFindings(t *testing.T) {
+	existing := &Baseline{Entries: []BaselineEntry{
+		{FilePath: "untouched.py", RuleID: "aws-access-key-id", LineHash: "old-hash"},
+	}}
+
+	// A scan of a different set of staged files: "untouched.py" wasn't
+	// among them, so it produces no findings at all this run.
+	findings := []Finding{
+		{FilePath: "other.py", RuleID: "github-pat", LineHash: "new-hash"},
+	}
+
+	entries := BuildBaselineEntries(findings, existing)
+	if len(entries) != 2 {
+		t.Fatalf("BuildBaselineEntries() = %d entries, want 2 (preserved + new)", len(entries))
+	}
+
+	var sawOld, sawNew bool
+	for _, e := range entries {
+		if e.FilePath == "untouched.py" && e.LineHash == "old-hash" {
+			sawOld = true
+		}
+		if e.FilePath == "other.py" && e.LineHash == "new-hash" {
+			sawNew = true
+		}
+	}
+	if !sawOld {
+		t.Error("BuildBaselineEntries() dropped an existing entry absent from this run's findings, want it appended rather than replaced")
+	}
+	if !sawNew {
+		t.Error("BuildBaselineEntries() missing the new finding")
+	}
+}
+
+func Test_Baseline_SuppressedCount(t *testing.T) {
+	b := &Baseline{index: map[string]*bool{
+		baselineKey("config.py", "anthropic-api-key", "deadbeef"): nil,
+	}}
+
+	if b.SuppressedCount() != 0 {
+		t.Fatalf("SuppressedCount() = %d before any Suppresses() call, want 0", b.SuppressedCount())
+	}
+
+	b.Suppresses(Finding{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: "deadbeef"})
+	b.Suppresses(Finding{FilePath: "config.py", RuleID: "anthropic-api-key", LineHash: "not-in-baseline"})
+
+	if got := b.SuppressedCount(); got != 1 {
+		t.Errorf("SuppressedCount() = %d, want 1 (only the matching Suppresses() call counts)", got)
+	}
+}
+
+func Test_Baseline_SuppressedCount_NilBaseline(t *testing.T) {
+	var b *Baseline
+	if b.SuppressedCount() != 0 {
+		t.Error("SuppressedCount() on a nil Baseline should be 0, not panic")
+	}
+}
+
+func Test_WriteBaseline_RoundTrips(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".secrets-baseline.json")
+	want := []BaselineEntry{{FilePath: "a.py", RuleID: "rule", LineHash: "h"}}
+
+	if err := WriteBaseline(path, want); err != nil {
+		t.Fatalf("WriteBaseline() error = %v", err)
+	}
+
+	b, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline() error = %v", err)
+	}
+	if len(b.Entries) != 1 || b.Entries[0].FilePath != "a.py" {
+		t.Errorf("LoadBaseline() after WriteBaseline() = %+v, want %+v", b.Entries, want)
+	}
+}
+
+func Test_AuditBaseline_PromptsOnlyMissingVerdicts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".secrets-baseline.json")
+	marked := false
+	entries := []BaselineEntry{
+		{FilePath: "a.py", RuleID: "rule", LineHash: "hash1", IsSecret: &marked},
+		{FilePath: "b.py", RuleID: "rule", LineHash: "hash2"},
+	}
+
+	in := bufio.NewScanner(strings.NewReader("y\n"))
+	out, err := os.CreateTemp(t.TempDir(), "audit-out")
+	if err != nil {
+		t.Fatalf("CreateTemp() error = %v", err)
+	}
+	defer out.Close()
+
+	got, err := AuditBaseline(path, entries, in, out)
+	if err != nil {
+		t.Fatalf("AuditBaseline() error = %v", err)
+	}
+
+	if got[0].IsSecret == nil || *got[0].IsSecret {
+		t.Error("AuditBaseline() overwrote an entry that already had a verdict")
+	}
+	if got[1].IsSecret == nil || !*got[1].IsSecret {
+		t.Error("AuditBaseline() did not record the 'y' answer for the entry missing a verdict")
+	}
+}
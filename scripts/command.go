@@ -0,0 +1,188 @@
+package main
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// quickCommitPrefilter is a cheap substring check so command lines that
+// obviously aren't a git commit (the overwhelming majority of Bash calls)
+// never pay for a shell parse.
+var quickCommitPrefilter = regexp.MustCompile(`(?i)git`)
+
+// Legacy regex-based detector, kept as a fallback for command lines the
+// shell parser can't make sense of (e.g. dialect quirks it doesn't model).
+var (
+	legacyCommandSeparatorRegex = regexp.MustCompile(`[;&|]+`)
+	legacyGitCommandRegex       = regexp.MustCompile(`(?i)^(?:\S+[/\\])?git(?:\.exe)?\b`)
+	legacyCommitWordRegex       = regexp.MustCompile(`(?i)\bcommit\b`)
+)
+
+// IsGitCommitCommand checks if a command string contains a git commit
+// operation. The command line is parsed as a POSIX-ish shell script via
+// mvdan.cc/sh so quoting, subshells, pipelines, and separators are handled
+// the way a real shell would, rather than by splitting on `[;&|]+`. Each
+// parsed call is resolved to its argv and matched against `git ... commit`,
+// skipping leading flags such as `-C <dir>` and `-c <key>=<value>`.
+func IsGitCommitCommand(command string) bool {
+	if !quickCommitPrefilter.MatchString(command) {
+		return false
+	}
+
+	file, err := syntax.NewParser().Parse(strings.NewReader(command), "")
+	if err != nil {
+		return legacyIsGitCommitCommand(command)
+	}
+
+	found := false
+	syntax.Walk(file, func(node syntax.Node) bool {
+		if found {
+			return false
+		}
+		if call, ok := node.(*syntax.CallExpr); ok && isGitCommitCall(resolveEnvPrefix(literalArgs(call))) {
+			found = true
+			return false
+		}
+		return true
+	})
+
+	return found
+}
+
+// literalArgs resolves a CallExpr's words to plain strings. Words containing
+// unresolvable parts (parameter expansions, command substitutions, etc.)
+// resolve to "" rather than being dropped, so positional counting stays
+// correct.
+func literalArgs(call *syntax.CallExpr) []string {
+	args := make([]string, len(call.Args))
+	for i, word := range call.Args {
+		args[i], _ = literalWord(word)
+	}
+	return args
+}
+
+// literalWord returns the literal string value of a word if every part of it
+// is a literal or quoted literal, and false otherwise.
+func literalWord(word *syntax.Word) (string, bool) {
+	var sb strings.Builder
+
+	for _, part := range word.Parts {
+		switch p := part.(type) {
+		case *syntax.Lit:
+			sb.WriteString(p.Value)
+		case *syntax.SglQuoted:
+			sb.WriteString(p.Value)
+		case *syntax.DblQuoted:
+			for _, inner := range p.Parts {
+				lit, ok := inner.(*syntax.Lit)
+				if !ok {
+					return "", false
+				}
+				sb.WriteString(lit.Value)
+			}
+		default:
+			return "", false
+		}
+	}
+
+	return sb.String(), true
+}
+
+// resolveEnvPrefix strips a leading "env [-flags] VAR=value..." prefix so
+// that "env GIT_AUTHOR_NAME=bot git commit" resolves to the same argv as
+// "git commit". Assignment-prefixed calls like "VAR=x git commit" are
+// already split into Assigns/Args by the parser and need no special casing.
+func resolveEnvPrefix(args []string) []string {
+	if len(args) == 0 || strings.ToLower(args[0]) != "env" {
+		return args
+	}
+
+	i := 1
+	for i < len(args) {
+		arg := args[i]
+		if arg == "" || strings.HasPrefix(arg, "-") || strings.Contains(arg, "=") {
+			i++
+			continue
+		}
+		break
+	}
+
+	return args[i:]
+}
+
+// gitValueTakingGlobalFlags are git's global options that consume the next
+// argv entry as their value when not given in `--flag=value` form. Missing
+// one here means the token after it (e.g. a path) gets mistaken for the
+// subcommand, so this must stay in sync with `git help` as new global flags
+// are added.
+var gitValueTakingGlobalFlags = map[string]bool{
+	"-C":             true,
+	"-c":             true,
+	"--git-dir":      true,
+	"--work-tree":    true,
+	"--namespace":    true,
+	"--super-prefix": true,
+	"--config-env":   true,
+	"--exec-path":    true,
+}
+
+// isGitCommitCall reports whether a resolved argv invokes "git ... commit",
+// skipping leading flags including value-taking global options such as
+// `-C <dir>`, `-c <key>=<value>`, and `--work-tree <dir>`.
+func isGitCommitCall(args []string) bool {
+	if len(args) == 0 || !isGitProgram(args[0]) {
+		return false
+	}
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == "" {
+			continue
+		}
+		if gitValueTakingGlobalFlags[arg] {
+			i++ // skip the flag's value
+			continue
+		}
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		return strings.EqualFold(arg, "commit")
+	}
+
+	return false
+}
+
+// isGitProgram reports whether argv[0] resolves to a git executable,
+// regardless of path prefix (/usr/bin/git) or .exe suffix (git.exe).
+func isGitProgram(arg string) bool {
+	base := strings.ToLower(path.Base(strings.ReplaceAll(arg, `\`, "/")))
+	base = strings.TrimSuffix(base, ".exe")
+	return base == "git"
+}
+
+// legacyIsGitCommitCommand is the original regex-based heuristic. It runs
+// only when the shell parser itself can't parse the command line, so we
+// fail toward scanning rather than silently skipping an unparseable commit.
+func legacyIsGitCommitCommand(command string) bool {
+	subcommands := legacyCommandSeparatorRegex.Split(command, -1)
+
+	for _, subcommand := range subcommands {
+		subcommand = strings.TrimSpace(subcommand)
+		if subcommand == "" {
+			continue
+		}
+
+		if !legacyGitCommandRegex.MatchString(subcommand) {
+			continue
+		}
+
+		if legacyCommitWordRegex.MatchString(subcommand) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,306 @@
+package main
+
+import "testing"
+
+// ---------------------------------------------------------------------------
+// TestIsGitCommitCommand
+// ---------------------------------------------------------------------------
+
+func Test_IsGitCommitCommand_Cases(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    bool
+	}{
+		// Commands that SHOULD match.
+		{
+			name:    "simple git commit",
+			command: "git commit",
+			want:    true,
+		},
+		{
+			name:    "git commit with message flag",
+			command: `git commit -m "message"`,
+			want:    true,
+		},
+		{
+			name:    "git commit with add-and-message flag",
+			command: `git commit -am "message"`,
+			want:    true,
+		},
+		{
+			name:    "git commit with message and amend",
+			command: `git commit -m "fix" --amend`,
+			want:    true,
+		},
+		{
+			name:    "git commit with allow-empty",
+			command: "git commit --allow-empty",
+			want:    true,
+		},
+		{
+			name:    "git commit with signing and message",
+			command: `git commit -S "key" -m "msg"`,
+			want:    true,
+		},
+		{
+			name:    "all caps GIT COMMIT",
+			command: "GIT COMMIT",
+			want:    true,
+		},
+		{
+			name:    "mixed case Git Commit",
+			command: `Git Commit -m "msg"`,
+			want:    true,
+		},
+		{
+			name:    "random case gIT cOMMIT",
+			command: "gIT cOMMIT",
+			want:    true,
+		},
+		{
+			name:    "unix path prefix /usr/bin/git commit",
+			command: "/usr/bin/git commit",
+			want:    true,
+		},
+		{
+			name:    "windows path prefix git.exe commit",
+			command: `C:\git\git.exe commit`,
+			want:    true,
+		},
+		{
+			name:    "git with -C flag before commit",
+			command: "git -C /path/to/repo commit -m 'msg'",
+			want:    true,
+		},
+		{
+			name:    "git with -c key=value flag before commit",
+			command: `git -c user.name=bot commit -m "msg"`,
+			want:    true,
+		},
+		{
+			name:    "git with --work-tree flag before commit",
+			command: "git --work-tree /tmp/repo commit -m 'msg'",
+			want:    true,
+		},
+		{
+			name:    "git with --git-dir flag before commit",
+			command: "git --git-dir /tmp/repo/.git commit -m 'msg'",
+			want:    true,
+		},
+		{
+			name:    "env-prefixed git commit",
+			command: "env GIT_AUTHOR_NAME=bot git commit -m 'msg'",
+			want:    true,
+		},
+		{
+			name:    "shell assignment-prefixed git commit",
+			command: "GIT_AUTHOR_NAME=bot git commit -m 'msg'",
+			want:    true,
+		},
+		{
+			name:    "backslash-escaped git commit",
+			command: `\git commit -m "msg"`,
+			want:    true,
+		},
+		{
+			name:    "commit message containing && is not a separate command",
+			command: `git commit -m "a && b"`,
+			want:    true,
+		},
+		{
+			name:    "commit message containing a semicolon",
+			command: `git commit -m "a; rm -rf /"`,
+			want:    true,
+		},
+
+		// Commands that should NOT match.
+		{
+			name:    "git push",
+			command: "git push",
+			want:    false,
+		},
+		{
+			name:    "git pull",
+			command: "git pull",
+			want:    false,
+		},
+		{
+			name:    "git add",
+			command: "git add file.txt",
+			want:    false,
+		},
+		{
+			name:    "git status",
+			command: "git status",
+			want:    false,
+		},
+		{
+			name:    "commit without git prefix",
+			command: `commit -m "msg"`,
+			want:    false,
+		},
+		{
+			name:    "just commit word",
+			command: "just commit",
+			want:    false,
+		},
+		{
+			name:    "empty string",
+			command: "",
+			want:    false,
+		},
+		{
+			name:    "git without subcommand",
+			command: "git",
+			want:    false,
+		},
+		{
+			name:    "echo with git and commit words in a quoted string",
+			command: `echo "git is for commit"`,
+			want:    false,
+		},
+		{
+			name:    "gitcommit no word boundary",
+			command: "gitcommit",
+			want:    false,
+		},
+		{
+			name:    "mygit prefix before git",
+			command: "mygit commit",
+			want:    false,
+		},
+		{
+			name:    "git with non-commit word mycommit",
+			command: "git mycommit",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsGitCommitCommand(tt.command)
+			if got != tt.want {
+				t.Errorf("IsGitCommitCommand(%q) = %v, want %v", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Additional IsGitCommitCommand edge cases
+// ---------------------------------------------------------------------------
+
+func Test_IsGitCommitCommand_CompoundCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    bool
+	}{
+		{
+			name:    "git add then git commit separated by semicolon",
+			command: "git add . ; git commit -m 'msg'",
+			want:    true,
+		},
+		{
+			name:    "git add then git commit separated by &&",
+			command: "git add . && git commit -m 'msg'",
+			want:    true,
+		},
+		{
+			name:    "git add then git commit separated by ||",
+			command: "git add . || git commit -m 'msg'",
+			want:    true,
+		},
+		{
+			name:    "git commit inside a subshell",
+			command: "(git commit -m 'msg')",
+			want:    true,
+		},
+		{
+			name:    "git commit inside a command substitution",
+			command: "echo $(git commit -m 'msg')",
+			want:    true,
+		},
+		{
+			name:    "git commit backgrounded",
+			command: "git commit -m 'msg' &",
+			want:    true,
+		},
+		{
+			name:    "git commit piped to cat",
+			command: "git commit -m 'msg' | cat",
+			want:    true,
+		},
+		{
+			name:    "multiple non-commit commands separated by semicolon",
+			command: "git add . ; git status ; git diff",
+			want:    false,
+		},
+		{
+			name:    "only non-git commands with commit word",
+			command: "echo commit ; ls -la",
+			want:    false,
+		},
+		{
+			name:    "commit word inside an unrelated subshell string",
+			command: `echo "a; git commit" | cat`,
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsGitCommitCommand(tt.command)
+			if got != tt.want {
+				t.Errorf("IsGitCommitCommand(%q) = %v, want %v", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestLegacyIsGitCommitCommand - fallback used when the shell parser fails
+// ---------------------------------------------------------------------------
+
+func Test_LegacyIsGitCommitCommand_Fallback(t *testing.T) {
+	// An unterminated quote is invalid shell syntax and will fail to parse;
+	// the legacy regex path should still flag the embedded git commit.
+	command := `git commit -m "unterminated`
+
+	if !legacyIsGitCommitCommand(command) {
+		t.Errorf("legacyIsGitCommitCommand(%q) = false, want true", command)
+	}
+
+	if !IsGitCommitCommand(command) {
+		t.Errorf("IsGitCommitCommand(%q) = false, want true (should fall back to legacy matcher)", command)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Benchmarks
+// ---------------------------------------------------------------------------
+
+func Benchmark_IsGitCommitCommand_SimpleCommit(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		IsGitCommitCommand(`git commit -m "test message"`)
+	}
+}
+
+func Benchmark_IsGitCommitCommand_NonCommit(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		IsGitCommitCommand("git push origin main")
+	}
+}
+
+func Benchmark_IsGitCommitCommand_CompoundCommand(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		IsGitCommitCommand("git add . && git commit -m 'msg' && git push")
+	}
+}
+
+func Benchmark_IsGitCommitCommand_NonGitPrefilterReject(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		IsGitCommitCommand("npm install && npm test")
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// externalDetectorPrefix is the binary name prefix DiscoverExternalDetectors
+// looks for, following the Docker CLI plugin convention (docker-<name>).
+const externalDetectorPrefix = "check-secrets-detector-"
+
+// externalDetectorTimeout bounds a single detector invocation, whether it's
+// answering --describe or scanning a file, so one misbehaving plugin can't
+// stall the hook indefinitely.
+const externalDetectorTimeout = 10 * time.Second
+
+// detectorManifest is the JSON an external detector prints in response to
+// "--describe".
+type detectorManifest struct {
+	ID            string   `json:"id"`
+	Version       string   `json:"version"`
+	FileGlobs     []string `json:"fileGlobs"`
+	HandlesBinary bool     `json:"handlesBinary"`
+}
+
+// externalFinding is one element of the JSON array an external detector
+// prints to stdout after scanning a file.
+type externalFinding struct {
+	RuleID   string `json:"ruleId"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Match    string `json:"match"`
+	Severity string `json:"severity"`
+}
+
+// ExternalDetector is a discovered detector plugin: its manifest plus the
+// binary path it was found at.
+type ExternalDetector struct {
+	detectorManifest
+	path string
+}
+
+// DiscoverExternalDetectors enumerates check-secrets-detector-* binaries on
+// PATH and in <projectRoot>/.claude/detectors, describes each via
+// "--describe", and returns the ones that answer with a valid manifest. A
+// name found in both locations is described only once, preferring the
+// project-local copy so a repo can pin its own version of a detector that
+// also happens to be on PATH. Discovery failures (a non-executable file, a
+// bad manifest, a timeout) are logged to stderr and the detector is skipped
+// rather than failing the hook.
+func DiscoverExternalDetectors(projectRoot string) []ExternalDetector {
+	candidates := make(map[string]string) // name -> path; later writes win
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		addDetectorCandidates(candidates, dir)
+	}
+	addDetectorCandidates(candidates, filepath.Join(projectRoot, ".claude", "detectors"))
+
+	names := make([]string, 0, len(candidates))
+	for name := range candidates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	detectors := make([]ExternalDetector, 0, len(names))
+	for _, name := range names {
+		path := candidates[name]
+		manifest, err := describeDetector(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping external detector %q: %v\n", path, err)
+			continue
+		}
+		detectors = append(detectors, ExternalDetector{detectorManifest: manifest, path: path})
+	}
+
+	return detectors
+}
+
+// addDetectorCandidates records every check-secrets-detector-* entry in dir,
+// overwriting any earlier candidate with the same name so a later-scanned
+// directory takes precedence.
+func addDetectorCandidates(candidates map[string]string, dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), externalDetectorPrefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Mode()&0o111 == 0 {
+			continue
+		}
+		candidates[entry.Name()] = filepath.Join(dir, entry.Name())
+	}
+}
+
+// describeDetector invokes path with "--describe" and parses its JSON manifest.
+func describeDetector(path string) (detectorManifest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), externalDetectorTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, "--describe").Output()
+	if err != nil {
+		return detectorManifest{}, fmt.Errorf("--describe failed: %w", err)
+	}
+
+	var manifest detectorManifest
+	if err := json.Unmarshal(out, &manifest); err != nil {
+		return detectorManifest{}, fmt.Errorf("invalid --describe manifest: %w", err)
+	}
+	if manifest.ID == "" {
+		return detectorManifest{}, fmt.Errorf("--describe manifest missing id")
+	}
+
+	return manifest, nil
+}
+
+// Matches reports whether filePath satisfies one of the detector's
+// fileGlobs, tested against the file's base name so a manifest can write
+// "*.pem" without worrying about directory structure.
+func (d ExternalDetector) Matches(filePath string) bool {
+	base := filepath.Base(filePath)
+	for _, glob := range d.FileGlobs {
+		if ok, err := filepath.Match(glob, base); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchingDetectors returns the subset of detectors whose fileGlobs match filePath.
+func matchingDetectors(detectors []ExternalDetector, filePath string) []ExternalDetector {
+	var matched []ExternalDetector
+	for _, d := range detectors {
+		if d.Matches(filePath) {
+			matched = append(matched, d)
+		}
+	}
+	return matched
+}
+
+// anyHandlesBinary reports whether any detector in the slice opted into
+// scanning binary/env content via its manifest's handlesBinary field.
+func anyHandlesBinary(detectors []ExternalDetector) bool {
+	for _, d := range detectors {
+		if d.HandlesBinary {
+			return true
+		}
+	}
+	return false
+}
+
+// filterFilesToScan drops the built-in binary/env skip list from
+// stagedFiles, unless some detector's fileGlobs match the file and its
+// manifest opted into handlesBinary.
+func filterFilesToScan(stagedFiles []string, detectors []ExternalDetector) []string {
+	filesToScan := make([]string, 0, len(stagedFiles))
+	for _, filePath := range stagedFiles {
+		if IsBinaryFile(filePath) || IsEnvFile(filePath) {
+			if !anyHandlesBinary(matchingDetectors(detectors, filePath)) {
+				continue
+			}
+		}
+		filesToScan = append(filesToScan, filePath)
+	}
+	return filesToScan
+}
+
+// runExternalDetector pipes content to the detector's stdin and parses the
+// JSON array of findings it prints to stdout.
+func runExternalDetector(d ExternalDetector, filePath, content string) ([]Finding, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), externalDetectorTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.path, filePath)
+	cmd.Stdin = strings.NewReader(content)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("detector %q failed: %w", d.ID, err)
+	}
+
+	var raw []externalFinding
+	if err := json.Unmarshal(stdout.Bytes(), &raw); err != nil {
+		return nil, fmt.Errorf("detector %q returned invalid findings JSON: %w", d.ID, err)
+	}
+
+	findings := make([]Finding, 0, len(raw))
+	for _, rf := range raw {
+		var lineHash string
+		if line, ok := extractLine(content, rf.Line); ok {
+			start, end := rf.Column-1, rf.Column-1+len(rf.Match)
+			if start >= 0 && end >= start && end <= len(line) {
+				lineHash = LineHash(line, start, end)
+			}
+		}
+
+		findings = append(findings, Finding{
+			RuleID:     rf.RuleID,
+			FilePath:   filePath,
+			Line:       rf.Line,
+			Column:     rf.Column,
+			Message:    fmt.Sprintf("Found potential %s (external detector %s)", rf.RuleID, d.ID),
+			Snippet:    redactSnippet(rf.Match),
+			LineHash:   lineHash,
+			SecretHash: SecretHash(rf.Match),
+		})
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDetectorSource is a standalone Go program used as a stand-in for a
+// real check-secrets-detector-* plugin: it answers "--describe" with a fixed
+// manifest matching "*.secret" files, and otherwise reads content on stdin
+// and reports a finding for every line containing the word "BLOCKME" - a
+// string the built-in patterns and .env heuristics would never flag.
+const fakeDetectorSource = `package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+type manifest struct {
+	ID        string   ` + "`json:\"id\"`" + `
+	FileGlobs []string ` + "`json:\"fileGlobs\"`" + `
+}
+
+type finding struct {
+	RuleID string ` + "`json:\"ruleId\"`" + `
+	Line   int    ` + "`json:\"line\"`" + `
+	Column int    ` + "`json:\"column\"`" + `
+	Match  string ` + "`json:\"match\"`" + `
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "--describe" {
+		_ = json.NewEncoder(os.Stdout).Encode(manifest{ID: "fake-detector", FileGlobs: []string{"*.secret"}})
+		return
+	}
+
+	var findings []finding
+	scanner := bufio.NewScanner(os.Stdin)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if idx := strings.Index(scanner.Text(), "BLOCKME"); idx >= 0 {
+			findings = append(findings, finding{RuleID: "fake-rule", Line: lineNo, Column: idx + 1, Match: "BLOCKME"})
+		}
+	}
+	_ = json.NewEncoder(os.Stdout).Encode(findings)
+}
+`
+
+var (
+	fakeDetectorOnce sync.Once
+	fakeDetectorPath string
+	fakeDetectorErr  string
+)
+
+// buildFakeDetector compiles fakeDetectorSource once per test run, mirroring
+// how TestMain builds the hook's own test binary, and returns the path to
+// the resulting executable.
+func buildFakeDetector(t *testing.T) string {
+	t.Helper()
+
+	fakeDetectorOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "check-secrets-detector-test")
+		if err != nil {
+			fakeDetectorErr = fmt.Sprintf("failed to create temp dir: %v", err)
+			return
+		}
+
+		srcPath := filepath.Join(dir, "main.go")
+		if err := os.WriteFile(srcPath, []byte(fakeDetectorSource), 0o644); err != nil {
+			fakeDetectorErr = fmt.Sprintf("failed to write fake detector source: %v", err)
+			return
+		}
+
+		binName := "check-secrets-detector-fake"
+		if runtime.GOOS == "windows" {
+			binName += ".exe"
+		}
+		path := filepath.Join(dir, binName)
+
+		cmd := exec.Command("go", "build", "-o", path, srcPath)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			fakeDetectorErr = fmt.Sprintf("failed to build fake detector: %v\n%s", err, out)
+			return
+		}
+		fakeDetectorPath = path
+	})
+
+	if fakeDetectorPath == "" {
+		t.Skipf("fake detector binary not available: %s", fakeDetectorErr)
+	}
+	return fakeDetectorPath
+}
+
+// installFakeDetector copies the compiled fake detector into
+// <dir>/.claude/detectors, the project-local location DiscoverExternalDetectors
+// searches alongside PATH.
+func installFakeDetector(t *testing.T, dir string) {
+	t.Helper()
+
+	src := buildFakeDetector(t)
+	detectorDir := filepath.Join(dir, ".claude", "detectors")
+	if err := os.MkdirAll(detectorDir, 0o755); err != nil {
+		t.Fatalf("failed to create detector dir: %v", err)
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatalf("failed to read fake detector binary: %v", err)
+	}
+	dst := filepath.Join(detectorDir, filepath.Base(src))
+	if err := os.WriteFile(dst, data, 0o755); err != nil {
+		t.Fatalf("failed to install fake detector binary: %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Test_Main_ExternalDetectorBlocksCommit - a project-local detector blocks a
+// commit the built-in patterns would have allowed
+// ---------------------------------------------------------------------------
+
+func Test_Main_ExternalDetectorBlocksCommit(t *testing.T) {
+	requireBinary(t)
+	dir := setupGitRepo(t)
+	installFakeDetector(t, dir)
+
+	writeTestFile(t, dir, "creds.secret", "token=BLOCKME\n")
+	gitAdd(t, dir, "creds.secret")
+
+	stdin := gitCommitInput("test external detector")
+	exitCode, stdout, _ := runBinaryInDir(t, dir, stdin, "CLAUDE_PROJECT_DIR="+dir)
+
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2 (blocked by external detector), got %d\nstdout: %s", exitCode, stdout)
+	}
+
+	var output hookOutputJSON
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("failed to parse stdout JSON: %v\nstdout: %q", err, stdout)
+	}
+	if output.HookSpecificOutput.PermissionDecision != "deny" {
+		t.Errorf("permissionDecision = %q, want %q", output.HookSpecificOutput.PermissionDecision, "deny")
+	}
+	if !strings.Contains(output.HookSpecificOutput.PermissionDecisionReason, "fake-rule") {
+		t.Errorf("permissionDecisionReason does not mention the external detector's rule id, got: %q",
+			output.HookSpecificOutput.PermissionDecisionReason)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Test_Main_ExternalDetectorIgnoresUnmatchedFiles - a detector whose
+// fileGlobs don't match the staged file shouldn't run at all
+// ---------------------------------------------------------------------------
+
+func Test_Main_ExternalDetectorIgnoresUnmatchedFiles(t *testing.T) {
+	requireBinary(t)
+	dir := setupGitRepo(t)
+	installFakeDetector(t, dir)
+
+	writeTestFile(t, dir, "notes.txt", "token=BLOCKME\n")
+	gitAdd(t, dir, "notes.txt")
+
+	stdin := gitCommitInput("test external detector unmatched")
+	exitCode, stdout, _ := runBinaryInDir(t, dir, stdin, "CLAUDE_PROJECT_DIR="+dir)
+
+	if exitCode != 0 {
+		t.Errorf("expected exit code 0 (detector's fileGlobs don't match .txt), got %d\nstdout: %s", exitCode, stdout)
+	}
+	if stdout != "" {
+		t.Errorf("expected empty stdout, got %q", stdout)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Test_ExternalDetector_Matches - glob matching against the file base name
+// ---------------------------------------------------------------------------
+
+func Test_ExternalDetector_Matches(t *testing.T) {
+	d := ExternalDetector{detectorManifest: detectorManifest{FileGlobs: []string{"*.pem", "id_rsa"}}}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"server.pem", true},
+		{"certs/server.pem", true},
+		{"id_rsa", true},
+		{"ssh/id_rsa", true},
+		{"server.pem.bak", false},
+		{"notes.txt", false},
+	}
+
+	for _, tt := range tests {
+		if got := d.Matches(tt.path); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Test_DiscoverExternalDetectors_ProjectLocalOverridesPath - a detector found
+// in both PATH and .claude/detectors is only described once, preferring the
+// project-local copy
+// ---------------------------------------------------------------------------
+
+func Test_DiscoverExternalDetectors_SkipsUndescribableBinaries(t *testing.T) {
+	dir := t.TempDir()
+	detectorDir := filepath.Join(dir, ".claude", "detectors")
+	if err := os.MkdirAll(detectorDir, 0o755); err != nil {
+		t.Fatalf("failed to create detector dir: %v", err)
+	}
+
+	// A file with the right name prefix that isn't executable shouldn't be
+	// picked up as a candidate at all.
+	notExecutable := filepath.Join(detectorDir, externalDetectorPrefix+"broken")
+	if err := os.WriteFile(notExecutable, []byte("not a real binary"), 0o644); err != nil {
+		t.Fatalf("failed to write non-executable detector stub: %v", err)
+	}
+
+	detectors := DiscoverExternalDetectors(dir)
+	for _, d := range detectors {
+		if d.ID == "broken" {
+			t.Errorf("expected non-executable detector stub to be skipped, got it in results: %+v", d)
+		}
+	}
+}
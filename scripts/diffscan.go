@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AddedHunk represents a contiguous range of lines added to the post-image
+// of a staged file, along with the 1-based line number where it starts in
+// that post-image.
+type AddedHunk struct {
+	StartLine int
+	Content   string
+}
+
+var diffHunkHeaderRegex = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// DiffHunks returns the added line ranges for path's staged changes via
+// "git diff --cached -U0 -- path". Using -U0 suppresses context lines, so
+// every "+" line belongs to content the user is actually introducing; lines
+// already present in HEAD are never scanned.
+func DiffHunks(path string) ([]AddedHunk, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), SubprocessTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "diff", "--cached", "-U0", "--", path)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseAddedHunks(string(output)), nil
+}
+
+// diffFileSectionRegex marks the start of each file's section in a
+// multi-file unified diff, so AllDiffHunks can split one combined diff back
+// into per-file hunks.
+var diffFileSectionRegex = regexp.MustCompile(`(?m)^diff --git `)
+
+// diffFileHeaderRegex extracts the post-image path from a file section's
+// "+++ b/<path>" header.
+var diffFileHeaderRegex = regexp.MustCompile(`(?m)^\+\+\+ b/(.+)$`)
+
+// AllDiffHunks returns every staged file's added line ranges from a single
+// "git diff --cached -U0" run covering the whole commit, rather than one
+// "git diff" per file (see DiffHunks). The combined unified diff is split
+// back into per-file sections keyed by post-image path. A file with no
+// added lines (e.g. a pure deletion, or a binary file reported as "Binary
+// files ... differ" with no "+++" header) has no entry in the result.
+func AllDiffHunks() (map[string][]AddedHunk, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), SubprocessTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "diff", "--cached", "-U0")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]AddedHunk)
+	for _, section := range diffFileSectionRegex.Split(string(output), -1) {
+		m := diffFileHeaderRegex.FindStringSubmatch(section)
+		if m == nil {
+			continue
+		}
+		if hunks := parseAddedHunks(section); len(hunks) > 0 {
+			result[m[1]] = hunks
+		}
+	}
+
+	return result, nil
+}
+
+// parseAddedHunks walks a unified diff produced with -U0 and collects runs
+// of added ("+") lines into AddedHunks, tracking the post-image line number
+// from each "@@ -a,b +c,d @@" header.
+func parseAddedHunks(diff string) []AddedHunk {
+	var hunks []AddedHunk
+	var current *AddedHunk
+	nextLine := 0
+
+	flush := func() {
+		if current != nil {
+			hunks = append(hunks, *current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			if m := diffHunkHeaderRegex.FindStringSubmatch(line); m != nil {
+				nextLine, _ = strconv.Atoi(m[1])
+			}
+		case strings.HasPrefix(line, "+++"):
+			// post-image file header, not content
+		case strings.HasPrefix(line, "+"):
+			if current == nil {
+				current = &AddedHunk{StartLine: nextLine}
+			}
+			current.Content += line[1:] + "\n"
+			nextLine++
+		default:
+			// deletions, pre-image file headers, and (with -U0) anything
+			// else never extend the current run of added lines
+			flush()
+		}
+	}
+	flush()
+
+	return hunks
+}
+
+// ScanAddedHunks runs the same secret detection as CheckFileForSecrets but
+// against a set of diff hunks instead of a whole file, so both pattern and
+// env-value issues reference real post-image line numbers and only fire on
+// lines the user is actually introducing.
+func ScanAddedHunks(filePath string, hunks []AddedHunk, envPatterns map[string]CompiledEnvPattern) ([]string, []string) {
+	patternIssues, envIssues, _ := ScanAddedHunksWithFindings(filePath, hunks, envPatterns)
+	return patternIssues, envIssues
+}
+
+// ScanAddedHunksWithFindings behaves like ScanAddedHunks but also returns the
+// structured Findings behind each issue string.
+func ScanAddedHunksWithFindings(filePath string, hunks []AddedHunk, envPatterns map[string]CompiledEnvPattern) ([]string, []string, []Finding) {
+	var patternIssues, envIssues []string
+	var findings []Finding
+
+	for _, hunk := range hunks {
+		p, e, f := checkContentForSecrets(filePath, hunk.Content, envPatterns, hunk.StartLine-1)
+		patternIssues = append(patternIssues, p...)
+		envIssues = append(envIssues, e...)
+		findings = append(findings, f...)
+	}
+
+	return patternIssues, envIssues, findings
+}
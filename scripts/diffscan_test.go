@@ -0,0 +1,266 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// gitCommitRepo commits whatever is currently staged in dir.
+func gitCommitRepo(t *testing.T, dir, message string) {
+	t.Helper()
+	cmd := exec.Command("git", "commit", "-m", message)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit failed: %v\n%s", err, out)
+	}
+}
+
+// diffHunksInDir runs DiffHunks with dir as the working directory, since
+// DiffHunks (like GetStagedFiles/GetStagedContent) operates relative to cwd.
+func diffHunksInDir(t *testing.T, dir, path string) ([]AddedHunk, error) {
+	t.Helper()
+
+	originalCwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("os.Getwd() error = %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("os.Chdir(%q) error = %v", dir, err)
+	}
+	defer func() { _ = os.Chdir(originalCwd) }()
+
+	return DiffHunks(path)
+}
+
+// ---------------------------------------------------------------------------
+// TestParseAddedHunks
+// ---------------------------------------------------------------------------
+
+func Test_ParseAddedHunks_Cases(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want []AddedHunk
+	}{
+		{
+			name: "new file, single hunk",
+			diff: "diff --git a/f.txt b/f.txt\n" +
+				"new file mode 100644\n" +
+				"index 0000000..ce01362\n" +
+				"--- /dev/null\n" +
+				"+++ b/f.txt\n" +
+				"@@ -0,0 +1 @@\n" +
+				"+hello\n",
+			want: []AddedHunk{{StartLine: 1, Content: "hello\n"}},
+		},
+		{
+			name: "appended lines after existing content",
+			diff: "diff --git a/f.txt b/f.txt\n" +
+				"--- a/f.txt\n" +
+				"+++ b/f.txt\n" +
+				"@@ -2,0 +3,2 @@ line2\n" +
+				"+line3\n" +
+				"+line4\n",
+			want: []AddedHunk{{StartLine: 3, Content: "line3\nline4\n"}},
+		},
+		{
+			name: "replaced line does not count the deletion",
+			diff: "diff --git a/f.txt b/f.txt\n" +
+				"--- a/f.txt\n" +
+				"+++ b/f.txt\n" +
+				"@@ -5 +5 @@ old\n" +
+				"-old line\n" +
+				"+new line\n",
+			want: []AddedHunk{{StartLine: 5, Content: "new line\n"}},
+		},
+		{
+			name: "two separate hunks in one file",
+			diff: "diff --git a/f.txt b/f.txt\n" +
+				"--- a/f.txt\n" +
+				"+++ b/f.txt\n" +
+				"@@ -1,0 +2 @@\n" +
+				"+added near top\n" +
+				"@@ -10,0 +12 @@\n" +
+				"+added near bottom\n",
+			want: []AddedHunk{
+				{StartLine: 2, Content: "added near top\n"},
+				{StartLine: 12, Content: "added near bottom\n"},
+			},
+		},
+		{
+			name: "pure deletion produces no hunks",
+			diff: "diff --git a/f.txt b/f.txt\n" +
+				"--- a/f.txt\n" +
+				"+++ b/f.txt\n" +
+				"@@ -3 +0,0 @@\n" +
+				"-removed line\n",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseAddedHunks(tt.diff)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseAddedHunks() returned %d hunks, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("hunk[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestDiffHunks - integration test against a real git repo
+// ---------------------------------------------------------------------------
+
+func Test_DiffHunks_OnlyReportsAddedLines(t *testing.T) {
+	dir := setupGitRepo(t)
+	writeTestFile(t, dir, "app.py", "line1\nline2\nline3\n")
+	gitAdd(t, dir, "app.py")
+	gitCommitRepo(t, dir, "base commit")
+
+	writeTestFile(t, dir, "app.py", "line1\nline2\nline3\napi_key = 'sk-ant-"+strings.Repeat("x", 30)+"'\n")
+	gitAdd(t, dir, "app.py")
+
+	hunks, err := diffHunksInDir(t, dir, "app.py")
+	if err != nil {
+		t.Fatalf("DiffHunks() error = %v", err)
+	}
+	if len(hunks) != 1 {
+		t.Fatalf("DiffHunks() returned %d hunks, want 1: %+v", len(hunks), hunks)
+	}
+	if hunks[0].StartLine != 4 {
+		t.Errorf("hunk StartLine = %d, want 4", hunks[0].StartLine)
+	}
+	if !strings.Contains(hunks[0].Content, "sk-ant-") {
+		t.Errorf("hunk content = %q, want it to contain the added secret", hunks[0].Content)
+	}
+}
+
+func Test_DiffHunks_UnchangedLinesNotReScanned(t *testing.T) {
+	dir := setupGitRepo(t)
+	preexisting := "legacy_key = 'sk-ant-" + strings.Repeat("y", 30) + "'\n"
+	writeTestFile(t, dir, "legacy.py", preexisting)
+	gitAdd(t, dir, "legacy.py")
+	gitCommitRepo(t, dir, "base commit with a legacy secret")
+
+	writeTestFile(t, dir, "legacy.py", preexisting+"print('no new secrets here')\n")
+	gitAdd(t, dir, "legacy.py")
+
+	hunks, err := diffHunksInDir(t, dir, "legacy.py")
+	if err != nil {
+		t.Fatalf("DiffHunks() error = %v", err)
+	}
+
+	envPatterns := map[string]CompiledEnvPattern{}
+	patternIssues, _ := ScanAddedHunks("legacy.py", hunks, envPatterns)
+	if len(patternIssues) != 0 {
+		t.Errorf("ScanAddedHunks() found %d issues in added content, want 0; the legacy secret should not be re-flagged: %v",
+			len(patternIssues), patternIssues)
+	}
+}
+
+func Test_AllDiffHunks_SplitsMultipleFiles(t *testing.T) {
+	dir := setupGitRepo(t)
+	writeTestFile(t, dir, "app.py", "line1\nline2\n")
+	writeTestFile(t, dir, "config.py", "a = 1\n")
+	gitAdd(t, dir, "app.py")
+	gitAdd(t, dir, "config.py")
+	gitCommitRepo(t, dir, "base commit")
+
+	writeTestFile(t, dir, "app.py", "line1\nline2\napi_key = 'sk-ant-"+strings.Repeat("x", 30)+"'\n")
+	writeTestFile(t, dir, "config.py", "a = 1\nb = 2\n")
+	gitAdd(t, dir, "app.py")
+	gitAdd(t, dir, "config.py")
+
+	originalCwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("os.Getwd() error = %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("os.Chdir(%q) error = %v", dir, err)
+	}
+	defer func() { _ = os.Chdir(originalCwd) }()
+
+	hunksByFile, err := AllDiffHunks()
+	if err != nil {
+		t.Fatalf("AllDiffHunks() error = %v", err)
+	}
+
+	if len(hunksByFile["app.py"]) != 1 || hunksByFile["app.py"][0].StartLine != 3 {
+		t.Errorf("AllDiffHunks()[\"app.py\"] = %+v, want one hunk starting at line 3", hunksByFile["app.py"])
+	}
+	if !strings.Contains(hunksByFile["app.py"][0].Content, "sk-ant-") {
+		t.Errorf("AllDiffHunks()[\"app.py\"] content = %q, want it to contain the added secret", hunksByFile["app.py"][0].Content)
+	}
+	if len(hunksByFile["config.py"]) != 1 || hunksByFile["config.py"][0].StartLine != 2 {
+		t.Errorf("AllDiffHunks()[\"config.py\"] = %+v, want one hunk starting at line 2", hunksByFile["config.py"])
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestScanAddedHunks
+// ---------------------------------------------------------------------------
+
+func Test_ScanAddedHunks_ReportsPostImageLineNumbers(t *testing.T) {
+	hunks := []AddedHunk{
+		{StartLine: 10, Content: "nothing interesting\napi_key = 'sk-ant-" + strings.Repeat("z", 30) + "'\n"},
+	}
+
+	patternIssues, _ := ScanAddedHunks("app.py", hunks, map[string]CompiledEnvPattern{})
+
+	assertContainsSubstring(t, patternIssues, "app.py:11 -")
+}
+
+// ---------------------------------------------------------------------------
+// TestHasFullScanFlag
+// ---------------------------------------------------------------------------
+
+func Test_HasFullScanFlag_Cases(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "no args", args: nil, want: false},
+		{name: "flag present", args: []string{"--full-scan"}, want: true},
+		{name: "flag among others", args: []string{"--verbose", "--full-scan"}, want: true},
+		{name: "flag absent", args: []string{"--verbose"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasFullScanFlag(tt.args); got != tt.want {
+				t.Errorf("hasFullScanFlag(%v) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_HasFullScanFlag_ScanModeEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		scanMode string
+		want     bool
+	}{
+		{name: "SCAN_MODE=full", scanMode: "full", want: true},
+		{name: "SCAN_MODE=diff is the default and has no effect", scanMode: "diff", want: false},
+		{name: "SCAN_MODE unset", scanMode: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SCAN_MODE", tt.scanMode)
+			if got := hasFullScanFlag(nil); got != tt.want {
+				t.Errorf("hasFullScanFlag(nil) with SCAN_MODE=%q = %v, want %v", tt.scanMode, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Entropy thresholds and token constraints for EntropyScanner. Each has a
+// matching SECRETS_ENTROPY_* environment variable (see envInt/envFloat)
+// that overrides it for callers who want to tune sensitivity per repo
+// without a recompile.
+const (
+	MinEntropyTokenLength  = 20
+	Base64EntropyThreshold = 4.5
+	HexEntropyThreshold    = 3.0
+	// GenericEntropyThreshold gates non-hex, non-base64-shaped values (env
+	// values, the generic secret/token pattern's captured value): lower than
+	// Base64EntropyThreshold since a mixed-case/punctuation/space charset
+	// carries less entropy per character at the same level of randomness.
+	GenericEntropyThreshold = 3.5
+)
+
+var (
+	tokenDelimiterRegex   = regexp.MustCompile(`[\s"'=,:;<>(){}\[\]]+`)
+	candidateCharsetRegex = regexp.MustCompile(`^[A-Za-z0-9+/=_\-]+$`)
+	hexCharsetRegex       = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+	// base64LikeCharsetRegex is a stricter base64-alphabet check than
+	// candidateCharsetRegex (no underscore/dash), used only by
+	// isLowEntropyValue to classify raw .env values: unlike source-code
+	// tokens, env values are often readable_identifiers with underscores,
+	// which candidateCharsetRegex's broader token charset would otherwise
+	// misclassify as base64-shaped and hold to an unfairly high bar.
+	base64LikeCharsetRegex = regexp.MustCompile(`^[A-Za-z0-9+/]+=*$`)
+
+	// entropyAllowlistPatterns matches high-entropy-looking tokens that are
+	// almost always benign: full git SHA-1 hashes, UUIDs, and long runs of a
+	// single placeholder character (e.g. "xxxxxxxxxxxxxxxxxxxx").
+	entropyAllowlistPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^[0-9a-fA-F]{40}$`),
+		regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+		regexp.MustCompile(`(?i)^(x|0|f)+$`),
+	}
+
+	// testFixtureLineRegex skips a whole line when it looks like test or
+	// example data rather than a real credential. Bounded on letters/digits
+	// rather than \b so it still matches inside a snake_case identifier like
+	// "dummy_key" or "test_value", where the adjoining underscore is not a
+	// \w boundary.
+	testFixtureLineRegex = regexp.MustCompile(`(?i)(^|[^a-z0-9])(example|dummy|test)($|[^a-z0-9])`)
+)
+
+// commonEnglishWords is a small dictionary of everyday words of length >= 6,
+// used by containsDictionaryWord to reject high-charset, high-entropy-looking
+// tokens that are actually just readable English (a concatenated passphrase,
+// a sentence with spaces stripped), not a generated secret. It's
+// deliberately short: a handful of very common words catches most
+// English-like false positives without the cost of a full wordlist.
+var commonEnglishWords = []string{
+	"password", "welcome", "correct", "battery", "staple",
+	"because", "through", "another", "example", "default",
+	"account", "service", "address", "message", "project", "session",
+	"product", "company", "country", "history", "picture", "problem",
+	"program", "quality", "several", "special", "student",
+}
+
+// containsDictionaryWord reports whether tok contains one of
+// commonEnglishWords, case-insensitively, as a substring.
+func containsDictionaryWord(tok string) bool {
+	lower := strings.ToLower(tok)
+	for _, word := range commonEnglishWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// envInt reads name as an int, falling back to fallback if it's unset or
+// not a valid integer.
+func envInt(name string, fallback int) int {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+// envFloat reads name as a float64, falling back to fallback if it's unset
+// or not a valid number.
+func envFloat(name string, fallback float64) float64 {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fallback
+	}
+	return f
+}
+
+// commentPrefixesByExt maps a lowercase file extension to the single-line
+// comment markers used by that language, so entropy scanning can cheaply
+// skip tokens that only appear in a comment.
+var commentPrefixesByExt = map[string][]string{
+	".go":   {"//"},
+	".js":   {"//"},
+	".ts":   {"//"},
+	".java": {"//"},
+	".c":    {"//"},
+	".cpp":  {"//"},
+	".rs":   {"//"},
+	".py":   {"#"},
+	".rb":   {"#"},
+	".sh":   {"#"},
+	".yaml": {"#"},
+	".yml":  {"#"},
+}
+
+// EntropyScanner flags high-entropy tokens that the fixed secretPatterns
+// list misses, such as unlabeled random hex or base64-ish strings.
+type EntropyScanner struct {
+	skipValues      map[string]struct{}
+	minLen          int
+	base64Threshold float64
+	hexThreshold    float64
+}
+
+// defaultEntropyScanner is used by CheckFileForSecrets and has no extra
+// skip list beyond the package's own skipValues.
+var defaultEntropyScanner = NewEntropyScanner(nil)
+
+// NewEntropyScanner creates an EntropyScanner that additionally suppresses
+// any token equal (case-insensitively) to one in skip, on top of the
+// shared skipValues dictionary used by FilterEnvValues. Its token-length
+// and entropy thresholds default to MinEntropyTokenLength,
+// Base64EntropyThreshold and HexEntropyThreshold, each overridable via the
+// SECRETS_ENTROPY_MIN_LEN, SECRETS_ENTROPY_BASE64 and SECRETS_ENTROPY_HEX
+// environment variables. SECRET_ENTROPY_THRESHOLD sets a single cutoff for
+// both alphabets at once; the alphabet-specific variables take precedence
+// over it where both are set.
+func NewEntropyScanner(skip map[string]struct{}) *EntropyScanner {
+	_, base64Default, hexDefault := resolveEntropyThresholds()
+
+	return &EntropyScanner{
+		skipValues:      skip,
+		minLen:          envInt("SECRETS_ENTROPY_MIN_LEN", MinEntropyTokenLength),
+		base64Threshold: base64Default,
+		hexThreshold:    hexDefault,
+	}
+}
+
+// resolveEntropyThresholds returns the generic/base64/hex Shannon-entropy
+// cutoffs, applying SECRET_ENTROPY_THRESHOLD as a single override for all
+// three and then the alphabet-specific SECRETS_ENTROPY_GENERIC/BASE64/HEX
+// variables on top, mirroring NewEntropyScanner's existing precedence.
+func resolveEntropyThresholds() (generic, base64, hex float64) {
+	generic, base64, hex = GenericEntropyThreshold, Base64EntropyThreshold, HexEntropyThreshold
+	if unified, ok := os.LookupEnv("SECRET_ENTROPY_THRESHOLD"); ok {
+		if f, err := strconv.ParseFloat(unified, 64); err == nil {
+			generic, base64, hex = f, f, f
+		}
+	}
+	generic = envFloat("SECRETS_ENTROPY_GENERIC", generic)
+	base64 = envFloat("SECRETS_ENTROPY_BASE64", base64)
+	hex = envFloat("SECRETS_ENTROPY_HEX", hex)
+	return generic, base64, hex
+}
+
+// isLowEntropyValue reports whether s's Shannon entropy falls below the
+// threshold appropriate for its apparent charset (hex, base64-ish, or
+// generic), used by FilterEnvValues to drop low-randomness placeholder
+// values like "changeme123456" that pass the length/digit checks but
+// clearly aren't real credentials.
+func isLowEntropyValue(s string) bool {
+	generic, base64, hex := resolveEntropyThresholds()
+
+	threshold := generic
+	switch {
+	case hexCharsetRegex.MatchString(s):
+		threshold = hex
+	case base64LikeCharsetRegex.MatchString(s):
+		threshold = base64
+	}
+
+	return shannonEntropy(s) < threshold
+}
+
+// ScanLine returns the high-entropy tokens found on a single line, after
+// stripping a same-line comment for ext if a language hint is available.
+// A line that looks like test or example data (per testFixtureLineRegex)
+// is skipped entirely, since those tokens are fixtures rather than leaked
+// credentials. A token repeated more than once on the same line (e.g. a key
+// assigned to a variable and then echoed in a log statement) is only
+// reported once.
+func (s *EntropyScanner) ScanLine(line, ext string) []string {
+	line = stripLineComment(line, ext)
+	if testFixtureLineRegex.MatchString(line) {
+		return nil
+	}
+
+	var findings []string
+	seen := make(map[string]struct{})
+	for _, tok := range tokenDelimiterRegex.Split(line, -1) {
+		if !s.isHighEntropyToken(tok) {
+			continue
+		}
+		if _, dup := seen[tok]; dup {
+			continue
+		}
+		seen[tok] = struct{}{}
+		findings = append(findings, tok)
+	}
+	return findings
+}
+
+// isHighEntropyToken reports whether tok looks like a secret: long enough,
+// made entirely of base64/hex-ish characters, not a known non-secret value
+// or allowlisted shape (git SHA, UUID, placeholder filler, a recognizable
+// English word), and with Shannon entropy above the threshold for its
+// apparent charset.
+func (s *EntropyScanner) isHighEntropyToken(tok string) bool {
+	if len(tok) < s.minLen {
+		return false
+	}
+	if !candidateCharsetRegex.MatchString(tok) {
+		return false
+	}
+
+	lower := strings.ToLower(tok)
+	if _, skip := skipValues[lower]; skip {
+		return false
+	}
+	if _, skip := s.skipValues[lower]; skip {
+		return false
+	}
+	for _, allow := range entropyAllowlistPatterns {
+		if allow.MatchString(tok) {
+			return false
+		}
+	}
+	if containsDictionaryWord(tok) {
+		return false
+	}
+
+	entropy := shannonEntropy(tok)
+	if hexCharsetRegex.MatchString(tok) {
+		return entropy >= s.hexThreshold
+	}
+	return entropy >= s.base64Threshold
+}
+
+// shannonEntropy computes H = -Σ p_i * log2(p_i) over the byte-frequency
+// distribution of s.
+func shannonEntropy(s string) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+
+	var freq [256]int
+	for i := 0; i < len(s); i++ {
+		freq[s[i]]++
+	}
+
+	length := float64(len(s))
+	entropy := 0.0
+	for _, count := range freq {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
+
+// stripLineComment trims everything from a same-line comment marker
+// onward, based on a cheap extension-derived language hint. It doesn't
+// track strings or block comments, so it's a heuristic, not a parser.
+func stripLineComment(line, ext string) string {
+	markers, ok := commentPrefixesByExt[strings.ToLower(ext)]
+	if !ok {
+		return line
+	}
+
+	cut := len(line)
+	for _, marker := range markers {
+		if idx := strings.Index(line, marker); idx >= 0 && idx < cut {
+			cut = idx
+		}
+	}
+
+	return line[:cut]
+}
+
+// entropyIssues runs the default EntropyScanner over content line by line
+// and returns issues in the same "filepath:line - description" format as
+// the regex-based pattern issues.
+func entropyIssues(filePath, content string, lineOffset int) []string {
+	findings := entropyFindings(filePath, content, lineOffset)
+
+	var issues []string
+	for _, f := range findings {
+		issues = append(issues, formatIssue(f.FilePath, f.Line, f.Message))
+	}
+
+	return issues
+}
+
+// entropyFindings runs the default EntropyScanner over content line by line
+// and returns the structured Findings behind entropyIssues's strings.
+func entropyFindings(filePath, content string, lineOffset int) []Finding {
+	ext := filepath.Ext(filePath)
+
+	var findings []Finding
+	for i, line := range strings.Split(content, "\n") {
+		lineNum := i + 1 + lineOffset
+		for _, tok := range defaultEntropyScanner.ScanLine(line, ext) {
+			start := strings.Index(line, tok)
+			findings = append(findings, Finding{
+				RuleID:     HighEntropyStringRuleID,
+				FilePath:   filePath,
+				Line:       lineNum,
+				Column:     start + 1,
+				Message:    "Found potential high-entropy string",
+				Snippet:    redactSnippet(tok),
+				LineHash:   LineHash(line, start, start+len(tok)),
+				SecretHash: SecretHash(tok),
+			})
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestShannonEntropy
+// ---------------------------------------------------------------------------
+
+func Test_ShannonEntropy_Cases(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantMin float64
+		wantMax float64
+	}{
+		{name: "empty string", input: "", wantMin: 0, wantMax: 0},
+		{name: "single repeated character has zero entropy", input: strings.Repeat("a", 40), wantMin: 0, wantMax: 0},
+		{name: "two alternating characters", input: strings.Repeat("ab", 20), wantMin: 0.9, wantMax: 1.1},
+		{name: "high-entropy random-looking hex", input: "4f3a9c21e8b7d6051fa2938cd17e0b44", wantMin: 3.0, wantMax: 4.0},
+		{name: "high-entropy random-looking base64", input: "xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE", wantMin: 4.5, wantMax: 5.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shannonEntropy(tt.input)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("shannonEntropy(%q) = %v, want in range [%v, %v]", tt.input, got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestEntropyScannerScanLine
+// ---------------------------------------------------------------------------
+
+func Test_EntropyScanner_ScanLine_Cases(t *testing.T) {
+	scanner := NewEntropyScanner(nil)
+
+	tests := []struct {
+		name      string
+		line      string
+		ext       string
+		wantFound bool
+	}{
+		{
+			name:      "short token below length threshold",
+			line:      "key = 'abc123'",
+			ext:       ".py",
+			wantFound: false,
+		},
+		{
+			name:      "clean prose",
+			line:      "this is just a normal sentence about nothing in particular",
+			ext:       ".md",
+			wantFound: false,
+		},
+		{
+			name:      "long random-looking base64 token",
+			line:      "token = 'xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE'",
+			ext:       ".py",
+			wantFound: true,
+		},
+		{
+			name:      "long hex token",
+			line:      "sha = '4f3a9c21e8b7d6051fa2938cd17e0b449d8c7a6f5e4d3c2b1a0918273645fabc'",
+			ext:       ".py",
+			wantFound: true,
+		},
+		{
+			name:      "repeated characters never flagged regardless of length",
+			line:      "padding = '" + strings.Repeat("a", 40) + "'",
+			ext:       ".py",
+			wantFound: false,
+		},
+		{
+			name:      "high entropy token inside a line comment is skipped",
+			line:      "// token = 'xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE'",
+			ext:       ".go",
+			wantFound: false,
+		},
+		{
+			name:      "high entropy token on a commented-out python line is skipped",
+			line:      "# token = 'xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE'",
+			ext:       ".py",
+			wantFound: false,
+		},
+		{
+			name:      "comment marker ignored for extensions without a hint",
+			line:      "token = 'xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE' # not a python file",
+			ext:       ".txt",
+			wantFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := len(scanner.ScanLine(tt.line, tt.ext)) > 0
+			if got != tt.wantFound {
+				t.Errorf("ScanLine(%q, %q) found = %v, want %v", tt.line, tt.ext, got, tt.wantFound)
+			}
+		})
+	}
+}
+
+func Test_EntropyScanner_ScanLine_AllowlistsBenignShapes(t *testing.T) {
+	scanner := NewEntropyScanner(nil)
+
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "full git SHA", line: "see commit 4f3a9c21e8b7d6051fa2938cd17e0b449d8c7a6f"},
+		{name: "UUID", line: "request_id = '550e8400-e29b-41d4-a716-446655440000'"},
+		{name: "placeholder run of x", line: "api_key = '" + strings.Repeat("x", 32) + "'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if found := scanner.ScanLine(tt.line, ".py"); len(found) != 0 {
+				t.Errorf("ScanLine(%q) found %v, want none (allowlisted shape)", tt.line, found)
+			}
+		})
+	}
+}
+
+func Test_EntropyScanner_ScanLine_DedupsRepeatedTokenOnSameLine(t *testing.T) {
+	scanner := NewEntropyScanner(nil)
+
+	token := "xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE"
+	line := "token = '" + token + "'; logged_token = '" + token + "'"
+
+	found := scanner.ScanLine(line, ".py")
+	if len(found) != 1 {
+		t.Errorf("ScanLine(%q) = %v, want exactly one entry for a token repeated twice on the same line", line, found)
+	}
+}
+
+func Test_EntropyScanner_ScanLine_RejectsDictionaryWordLikeTokens(t *testing.T) {
+	scanner := NewEntropyScanner(nil)
+
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "contains 'password'", line: "value = 'SuperPasswordRandomzzzz1234567890'"},
+		{name: "contains 'company'", line: "value = 'AcmeCompanyRandomzzzz1234567890ab'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if found := scanner.ScanLine(tt.line, ".py"); len(found) != 0 {
+				t.Errorf("ScanLine(%q) found %v, want none (contains a common dictionary word)", tt.line, found)
+			}
+		})
+	}
+}
+
+func Test_EntropyScanner_ScanLine_SkipsTestFixtureLines(t *testing.T) {
+	scanner := NewEntropyScanner(nil)
+
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "EXAMPLE marker", line: "EXAMPLE token = 'xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE'"},
+		{name: "dummy marker", line: "dummy_key = 'xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE'"},
+		{name: "test marker", line: "test_value = 'xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if found := scanner.ScanLine(tt.line, ".py"); len(found) != 0 {
+				t.Errorf("ScanLine(%q) found %v, want none (test/fixture marker on the line)", tt.line, found)
+			}
+		})
+	}
+}
+
+func Test_NewEntropyScanner_HonorsEnvOverrides(t *testing.T) {
+	t.Setenv("SECRETS_ENTROPY_MIN_LEN", "200")
+	scanner := NewEntropyScanner(nil)
+
+	line := "token = 'xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE'"
+	if found := scanner.ScanLine(line, ".py"); len(found) != 0 {
+		t.Errorf("ScanLine() found %v with SECRETS_ENTROPY_MIN_LEN=200, want none (token shorter than override)", found)
+	}
+}
+
+func Test_NewEntropyScanner_UnifiedThresholdEnvIsOverriddenBySpecificOnes(t *testing.T) {
+	t.Setenv("SECRET_ENTROPY_THRESHOLD", "100")
+	t.Setenv("SECRETS_ENTROPY_BASE64", "4.5")
+
+	scanner := NewEntropyScanner(nil)
+	line := "token = 'xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE'"
+	if found := scanner.ScanLine(line, ".py"); len(found) == 0 {
+		t.Error("ScanLine() found none, want the specific SECRETS_ENTROPY_BASE64 override (4.5) to win over SECRET_ENTROPY_THRESHOLD (100)")
+	}
+}
+
+func Test_EnvInt_FallsBackOnInvalidOrUnsetValue(t *testing.T) {
+	t.Setenv("SECRETS_ENTROPY_MIN_LEN", "not-a-number")
+	if got := envInt("SECRETS_ENTROPY_MIN_LEN", 20); got != 20 {
+		t.Errorf("envInt() = %d, want fallback 20 for an invalid value", got)
+	}
+
+	if got := envInt("SECRETS_ENTROPY_DOES_NOT_EXIST", 20); got != 20 {
+		t.Errorf("envInt() = %d, want fallback 20 when unset", got)
+	}
+}
+
+func Test_EntropyScanner_ScanLine_SkipsKnownNonSecretValues(t *testing.T) {
+	scanner := NewEntropyScanner(map[string]struct{}{
+		"not_a_secret_but_quite_long_value_here": {},
+	})
+
+	line := "value = 'not_a_secret_but_quite_long_value_here'"
+	if found := scanner.ScanLine(line, ".py"); len(found) != 0 {
+		t.Errorf("ScanLine() found %v, want none (token is in the caller-supplied skip list)", found)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestStripLineComment
+// ---------------------------------------------------------------------------
+
+func Test_StripLineComment_Cases(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		ext  string
+		want string
+	}{
+		{
+			name: "go line comment",
+			line: `x := 1 // a comment`,
+			ext:  ".go",
+			want: "x := 1 ",
+		},
+		{
+			name: "python line comment",
+			line: "x = 1 # a comment",
+			ext:  ".py",
+			want: "x = 1 ",
+		},
+		{
+			name: "unknown extension is left untouched",
+			line: "x = 1 # not stripped",
+			ext:  ".txt",
+			want: "x = 1 # not stripped",
+		},
+		{
+			name: "no comment marker present",
+			line: "x = 1",
+			ext:  ".go",
+			want: "x = 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stripLineComment(tt.line, tt.ext)
+			if got != tt.want {
+				t.Errorf("stripLineComment(%q, %q) = %q, want %q", tt.line, tt.ext, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestCheckFileForSecrets entropy integration
+// ---------------------------------------------------------------------------
+
+func Test_CheckFileForSecrets_EntropyFinding(t *testing.T) {
+	content := "auth_token = 'xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE'\n"
+	patternIssues, _ := CheckFileForSecrets("service.py", content, map[string]CompiledEnvPattern{})
+
+	assertContainsSubstring(t, patternIssues, "high-entropy string")
+	assertContainsSubstring(t, patternIssues, "service.py:1 -")
+}
+
+// ---------------------------------------------------------------------------
+// TestIsLowEntropyValue
+// ---------------------------------------------------------------------------
+
+func Test_IsLowEntropyValue_Cases(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{"low-entropy placeholder", "changeme123456", true},
+		{"repeated-char token", "aaaaaaaaaaaaaaaaaaaaaaaaaaaa", true},
+		{"readable phrase with underscores clears the generic bar", "this_is_a_long_secret_value_that_should_not_be_filtered", false},
+		{"random-looking base64-ish value", "xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE", false},
+		{"low-entropy hex", "00000000000000000000000000000000000000", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLowEntropyValue(tt.value); got != tt.want {
+				t.Errorf("isLowEntropyValue(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_IsLowEntropyValue_HonorsUnifiedOverride(t *testing.T) {
+	t.Setenv("SECRET_ENTROPY_THRESHOLD", "100")
+
+	if !isLowEntropyValue("this_is_a_long_secret_value_that_should_not_be_filtered") {
+		t.Error("isLowEntropyValue() = false, want true with SECRET_ENTROPY_THRESHOLD=100 raising every bar")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestCheckFileForSecrets MinEntropy-gated patterns
+// ---------------------------------------------------------------------------
+
+func Test_CheckContentForSecrets_MinEntropySuppressesLowEntropyMatch(t *testing.T) {
+	content := "secret = 'aaaaaaaaaaaaaaaaaaaaaaaa'\n"
+	patternIssues, _ := CheckFileForSecrets("config.py", content, map[string]CompiledEnvPattern{})
+
+	assertNotContainsSubstring(t, patternIssues, "generic-secret-token")
+}
+
+func Test_CheckContentForSecrets_MinEntropyReportsHighEntropyMatchWithValue(t *testing.T) {
+	content := "secret = 'xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE'\n"
+	patternIssues, _ := CheckFileForSecrets("config.py", content, map[string]CompiledEnvPattern{})
+
+	assertContainsSubstring(t, patternIssues, "generic-secret-token")
+	assertContainsSubstring(t, patternIssues, "entropy=")
+}
+
+// ---------------------------------------------------------------------------
+// Benchmarks
+// ---------------------------------------------------------------------------
+
+func Benchmark_ShannonEntropy(b *testing.B) {
+	s := "xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		shannonEntropy(s)
+	}
+}
+
+func Benchmark_EntropyScanner_ScanLine(b *testing.B) {
+	scanner := NewEntropyScanner(nil)
+	line := "token = 'xK9p2LqW8zR4mN7vT1yB6hC3dF5sJ0aE', other = 'plain text value here'"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scanner.ScanLine(line, ".py")
+	}
+}
@@ -9,7 +9,11 @@ import (
 // ParseEnvFile reads a .env file and returns a dictionary of key-value pairs.
 // Returns an empty map if the file does not exist or cannot be read.
 // Handles: comments (#), empty lines, quoted values (single/double),
-// multiple equals signs, whitespace trimming.
+// multiple equals signs, whitespace trimming. A leading comment block
+// containing "security-hooks:ignore-file" skips the file entirely, and a
+// line carrying a "security-hooks:allow" directive (see allowdirective.go)
+// is excluded from the result, since both mark a value as deliberately
+// committed rather than a leak.
 func ParseEnvFile(envPath string) map[string]string {
 	envVars := make(map[string]string)
 
@@ -20,12 +24,27 @@ func ParseEnvFile(envPath string) map[string]string {
 	}
 	defer func() { _ = file.Close() }()
 
+	var sawContent bool
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
+		// Skip empty lines
+		if line == "" {
+			continue
+		}
+
+		// Comments before any real content can opt the whole file out
+		if strings.HasPrefix(line, "#") {
+			if !sawContent && strings.Contains(line, "security-hooks:ignore-file") {
+				return make(map[string]string)
+			}
+			continue
+		}
+		sawContent = true
+
+		// A line explicitly marked allowed is never treated as a secret
+		if _, ok := parseAllowDirective(line); ok {
 			continue
 		}
 
@@ -59,8 +78,11 @@ func ParseEnvFile(envPath string) map[string]string {
 }
 
 // FilterEnvValues filters env vars to only those likely to be secrets.
-// Removes: values shorter than MinSecretLength, values in skipValues (case-insensitive),
-// and numeric-only values.
+// Removes: values shorter than MinSecretLength, values in skipValues
+// (case-insensitive), numeric-only values, and values whose Shannon entropy
+// falls below the threshold for their apparent charset (see
+// isLowEntropyValue), which catches low-randomness placeholders like
+// "changeme123456" that the other checks miss.
 func FilterEnvValues(envVars map[string]string) map[string]string {
 	filtered := make(map[string]string)
 
@@ -80,6 +102,12 @@ func FilterEnvValues(envVars map[string]string) map[string]string {
 			continue
 		}
 
+		// Skip low-entropy values (e.g. "changeme123456"): real secrets are
+		// close to random, so low entropy is a strong non-secret signal
+		if isLowEntropyValue(value) {
+			continue
+		}
+
 		filtered[key] = value
 	}
 
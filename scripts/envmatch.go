@@ -0,0 +1,110 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// envMatchType selects how a CompiledEnvPattern looks for its value in
+// scanned content. Most .env values need no regex engine at all: they're
+// plain literals, and a regexp.Regexp's overhead (state machine setup, byte
+// class matching) is wasted on a simple substring scan.
+type envMatchType int
+
+const (
+	// envMatchExactLiteral is a plain strings.Index substring search, no
+	// word-boundary check, for values whose own first/last byte already
+	// isn't a word character (e.g. "user:pass@host" from a connection
+	// string) - that edge already anchors the match about as well as a
+	// \b would in practice, so the boundary check below is skipped.
+	envMatchExactLiteral envMatchType = iota
+	// envMatchWordBoundaryLiteral is a strings.Index substring search plus
+	// a manual check that the bytes immediately before/after the match
+	// aren't themselves word characters, replicating `\b<literal>\b`
+	// without going through regexp.
+	envMatchWordBoundaryLiteral
+	// envMatchRegexp falls back to a compiled *regexp.Regexp, for values
+	// containing characters regexp.QuoteMeta would escape - a literal
+	// scan can't express those.
+	envMatchRegexp
+)
+
+// CompiledEnvPattern is one .env value ready to search for in scanned
+// content, dispatching to the cheapest matching strategy its value allows.
+// Built by compileEnvPattern; CompileEnvPatterns builds one per env value.
+type CompiledEnvPattern struct {
+	matchType envMatchType
+	literal   string
+	re        *regexp.Regexp
+}
+
+// regexMetacharacters are the bytes regexp.QuoteMeta escapes; a value
+// containing any of them can't be searched for with a plain literal scan.
+const regexMetacharacters = `\.+*?()|[]{}^$`
+
+// isEnvWordByte reports whether b is a character regexp's \b treats as a
+// word character.
+func isEnvWordByte(b byte) bool {
+	return b == '_' ||
+		(b >= 'a' && b <= 'z') ||
+		(b >= 'A' && b <= 'Z') ||
+		(b >= '0' && b <= '9')
+}
+
+// compileEnvPattern picks the cheapest matchType for value and builds the
+// CompiledEnvPattern around it.
+func compileEnvPattern(value string) CompiledEnvPattern {
+	if value == "" {
+		return CompiledEnvPattern{matchType: envMatchExactLiteral}
+	}
+
+	if strings.ContainsAny(value, regexMetacharacters) {
+		pattern := `\b` + regexp.QuoteMeta(value) + `\b`
+		return CompiledEnvPattern{matchType: envMatchRegexp, re: regexp.MustCompile(pattern)}
+	}
+
+	if isEnvWordByte(value[0]) || isEnvWordByte(value[len(value)-1]) {
+		return CompiledEnvPattern{matchType: envMatchWordBoundaryLiteral, literal: value}
+	}
+
+	return CompiledEnvPattern{matchType: envMatchExactLiteral, literal: value}
+}
+
+// Find returns the [start, end) byte ranges where p matches in content, in
+// the same shape as regexp.Regexp.FindAllStringIndex(content, -1), so
+// callers don't need to know which matchType handled it.
+func (p CompiledEnvPattern) Find(content string) [][]int {
+	if p.matchType == envMatchRegexp {
+		return p.re.FindAllStringIndex(content, -1)
+	}
+
+	literal := p.literal
+	if literal == "" {
+		return nil
+	}
+
+	var matches [][]int
+	for start := 0; ; {
+		idx := strings.Index(content[start:], literal)
+		if idx < 0 {
+			break
+		}
+		matchStart := start + idx
+		matchEnd := matchStart + len(literal)
+
+		if p.matchType == envMatchWordBoundaryLiteral {
+			if matchStart > 0 && isEnvWordByte(content[matchStart-1]) == isEnvWordByte(literal[0]) {
+				start = matchStart + 1
+				continue
+			}
+			if matchEnd < len(content) && isEnvWordByte(content[matchEnd]) == isEnvWordByte(literal[len(literal)-1]) {
+				start = matchStart + 1
+				continue
+			}
+		}
+
+		matches = append(matches, []int{matchStart, matchEnd})
+		start = matchEnd
+	}
+	return matches
+}
@@ -0,0 +1,76 @@
+package main
+
+import "testing"
+
+func Test_CompileEnvPattern_MatchType(t *testing.T) {
+	cases := []struct {
+		name  string
+		value string
+		want  envMatchType
+	}{
+		{"alnum only", "my_secret_value_123456789", envMatchWordBoundaryLiteral},
+		{"starts and ends with punctuation", "@host:1234@", envMatchExactLiteral},
+		{"leading word char, trailing punctuation", "secret@", envMatchWordBoundaryLiteral},
+		{"contains regex metacharacters", "value0with+special9chars", envMatchRegexp},
+		{"empty value", "", envMatchExactLiteral},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := compileEnvPattern(tc.value)
+			if got.matchType != tc.want {
+				t.Errorf("compileEnvPattern(%q).matchType = %v, want %v", tc.value, got.matchType, tc.want)
+			}
+		})
+	}
+}
+
+func Test_CompiledEnvPattern_Find(t *testing.T) {
+	t.Run("word boundary literal matches whole word only", func(t *testing.T) {
+		p := compileEnvPattern("secret")
+
+		if len(p.Find("the secret is here")) != 1 {
+			t.Error("expected a match for 'secret' as a standalone word")
+		}
+		if len(p.Find("my_secret_value")) != 0 {
+			t.Error("expected no match for 'secret' embedded in a longer identifier")
+		}
+	})
+
+	t.Run("exact literal matches without boundary checks", func(t *testing.T) {
+		p := compileEnvPattern("@host:1234@")
+
+		matches := p.Find("connect to @host:1234@ now")
+		if len(matches) != 1 {
+			t.Fatalf("Find() = %v, want 1 match", matches)
+		}
+	})
+
+	t.Run("regexp fallback escapes metacharacters", func(t *testing.T) {
+		p := compileEnvPattern("value0with+special9chars")
+
+		if len(p.Find("value0with+special9chars")) == 0 {
+			t.Error("expected a match for the literal value containing a '+'")
+		}
+		if len(p.Find("value0withhhspecial9chars")) != 0 {
+			t.Error("'+' should not be treated as a regex quantifier")
+		}
+	})
+
+	t.Run("multiple non-overlapping matches", func(t *testing.T) {
+		p := compileEnvPattern("token123")
+
+		matches := p.Find("token123 appears twice: token123")
+		if len(matches) != 2 {
+			t.Fatalf("Find() = %v, want 2 matches", matches)
+		}
+	})
+
+	t.Run("empty value never matches", func(t *testing.T) {
+		p := compileEnvPattern("")
+
+		if matches := p.Find("anything at all"); len(matches) != 0 {
+			t.Errorf("Find() = %v, want no matches for an empty pattern", matches)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// FileFilterConfig is the "fileFilter" section of .security-hooks.json,
+// letting a repo skip scanning files by extension, path, size, or content in
+// ways the built-in IsBinaryFile/defaultSkipMatcher checks don't cover - for
+// example a generated-lockfile extension, a vendored path the repo doesn't
+// want excludedPaths to govern, or a known test fixture that would otherwise
+// trip the scanner on every run.
+type FileFilterConfig struct {
+	// BlacklistedExtensions are file extensions (e.g. ".lock", ".snap")
+	// skipped outright, independent of binaryExtensions.
+	BlacklistedExtensions []string `json:"blacklistedExtensions,omitempty"`
+	// BlacklistedPaths are gitignore-style patterns, same syntax as
+	// ExcludedPaths, matched against a file's path. Overlaps ExcludedPaths'
+	// purpose by design; see RepoConfig.ExcludedPaths for which one a repo
+	// should reach for.
+	BlacklistedPaths []string `json:"blacklistedPaths,omitempty"`
+	// BlacklistedStrings are literal substrings that, if present anywhere in
+	// a file's content, cause the whole file to be skipped.
+	BlacklistedStrings []string `json:"blacklistedStrings,omitempty"`
+	// MaxFileSizeBytes overrides MaxFileSize for this repo's scan, if set.
+	MaxFileSizeBytes int64 `json:"maxFileSizeBytes,omitempty"`
+}
+
+// FileFilter is a FileFilterConfig compiled and ready to apply via
+// ShouldSkip. A nil *FileFilter is valid and never skips anything, so
+// callers can hold one unconditionally without a separate "configured" check.
+type FileFilter struct {
+	extensions map[string]struct{}
+	paths      []ignoreRule
+	strings    []string
+	maxSize    int64
+}
+
+// compileFileFilter compiles cfg into a *FileFilter, or nil if cfg declares
+// no filtering at all.
+func compileFileFilter(cfg FileFilterConfig) *FileFilter {
+	if len(cfg.BlacklistedExtensions) == 0 && len(cfg.BlacklistedPaths) == 0 &&
+		len(cfg.BlacklistedStrings) == 0 && cfg.MaxFileSizeBytes == 0 {
+		return nil
+	}
+
+	f := &FileFilter{maxSize: cfg.MaxFileSizeBytes}
+
+	if len(cfg.BlacklistedExtensions) > 0 {
+		f.extensions = make(map[string]struct{}, len(cfg.BlacklistedExtensions))
+		for _, ext := range cfg.BlacklistedExtensions {
+			f.extensions[defaultPathPolicy.Normalize(ext)] = struct{}{}
+		}
+	}
+
+	f.paths = compilePatternList(cfg.BlacklistedPaths, "fileFilter.blacklistedPaths")
+
+	for _, s := range cfg.BlacklistedStrings {
+		if s != "" {
+			f.strings = append(f.strings, s)
+		}
+	}
+
+	return f
+}
+
+// ShouldSkip reports whether filePath/content should be skipped by f, and a
+// short reason for diagnostics. A nil *FileFilter never skips.
+func (f *FileFilter) ShouldSkip(filePath, content string) (bool, string) {
+	if f == nil {
+		return false, ""
+	}
+
+	if len(f.extensions) > 0 {
+		ext := defaultPathPolicy.Normalize(filepath.Ext(filePath))
+		if _, ok := f.extensions[ext]; ok {
+			return true, "blacklisted extension"
+		}
+	}
+
+	if len(f.paths) > 0 {
+		var matched *ignoreRule
+		applyRules(f.paths, filepath.ToSlash(filePath), &matched)
+		if matched != nil {
+			return true, "blacklisted path"
+		}
+	}
+
+	if f.maxSize > 0 && int64(len(content)) > f.maxSize {
+		return true, "exceeds fileFilter.maxFileSizeBytes"
+	}
+
+	for _, s := range f.strings {
+		if strings.Contains(content, s) {
+			return true, "blacklisted content"
+		}
+	}
+
+	return false, ""
+}
@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func Test_CompileFileFilter_NilForEmptyConfig(t *testing.T) {
+	if f := compileFileFilter(FileFilterConfig{}); f != nil {
+		t.Errorf("compileFileFilter(empty) = %v, want nil", f)
+	}
+}
+
+func Test_FileFilter_ShouldSkip_NilFilterNeverSkips(t *testing.T) {
+	var f *FileFilter
+	if skip, reason := f.ShouldSkip("main.go", "package main"); skip {
+		t.Errorf("ShouldSkip on nil filter = (%v, %q), want (false, \"\")", skip, reason)
+	}
+}
+
+func Test_FileFilter_ShouldSkip_Cases(t *testing.T) {
+	f := compileFileFilter(FileFilterConfig{
+		BlacklistedExtensions: []string{".lock"},
+		BlacklistedPaths:      []string{"fixtures/"},
+		BlacklistedStrings:    []string{"DO-NOT-SCAN"},
+		MaxFileSizeBytes:      16,
+	})
+	if f == nil {
+		t.Fatal("compileFileFilter(non-empty) = nil, want a *FileFilter")
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		content  string
+		wantSkip bool
+	}{
+		{name: "blacklisted extension", path: "yarn.lock", content: "short", wantSkip: true},
+		{name: "blacklisted path", path: "fixtures/sample.go", content: "short", wantSkip: true},
+		{name: "blacklisted string anywhere in content", path: "main.go", content: "// DO-NOT-SCAN marker", wantSkip: true},
+		{name: "content exceeds max file size", path: "main.go", content: "this content is definitely over sixteen bytes", wantSkip: true},
+		{name: "unrelated file scanned normally", path: "main.go", content: "short", wantSkip: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skip, reason := f.ShouldSkip(tt.path, tt.content)
+			if skip != tt.wantSkip {
+				t.Errorf("ShouldSkip(%q, ...) = (%v, %q), want skip=%v", tt.path, skip, reason, tt.wantSkip)
+			}
+			if skip && reason == "" {
+				t.Errorf("ShouldSkip(%q, ...) = true with empty reason", tt.path)
+			}
+		})
+	}
+}
@@ -1,51 +1,247 @@
 package main
 
 import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
+// binaryContentSniffLen is how many leading bytes of a file IsBinaryFileByContent
+// reads before sniffing; it's enough for http.DetectContentType and every magic
+// number below.
+const binaryContentSniffLen = 512
+
+// nulScanLen is how far into a file the NUL-byte heuristic looks. Wider than
+// binaryContentSniffLen because NUL bytes in genuinely binary formats can
+// start well past the first 512 bytes (e.g. after a text-like header).
+const nulScanLen = 8192
+
+// binaryMagicNumbers holds byte signatures for formats that either
+// http.DetectContentType mislabels or that are technically valid ASCII but
+// should still be treated as binary (e.g. ASCII-armored PGP blocks).
+var binaryMagicNumbers = [][]byte{
+	[]byte("%PDF-"),                              // PDF
+	{0xCA, 0xFE, 0xBA, 0xBE},                      // Java class
+	{0x7F, 'E', 'L', 'F'},                         // ELF
+	{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}, // PNG
+	{'P', 'K', 0x03, 0x04},                        // ZIP/JAR
+	{0xFE, 0xED, 0xFA, 0xCE},                      // Mach-O (32-bit)
+	{0xFE, 0xED, 0xFA, 0xCF},                      // Mach-O (64-bit)
+	[]byte("-----BEGIN PGP"),                      // ASCII-armored PGP
+}
+
 // IsEnvFile checks if a file path refers to an environment file that should be skipped.
-// Handles both Unix (/) and Windows (\) path separators.
+// Handles both Unix (/) and Windows (\) path separators. Also consults
+// defaultSkipMatcher so a .securityhooksignore pattern can mark additional
+// paths as env-like without recompiling.
 func IsEnvFile(filePath string) bool {
+	if ok, _ := isEnvFileReason(filePath); ok {
+		return true
+	}
+	ok, _ := defaultSkipMatcher.Match(filePath)
+	return ok
+}
+
+// isEnvFileReason is the built-in (non-user-pattern) check behind IsEnvFile,
+// also used directly by ShouldSkip so it can report a specific reason.
+func isEnvFileReason(filePath string) (bool, string) {
 	// Replace all backslashes with forward slashes for consistent handling
 	normalizedPath := strings.ReplaceAll(filePath, "\\", "/")
 
 	// Get the filename (last component after splitting on /)
 	parts := strings.Split(normalizedPath, "/")
-	filename := parts[len(parts)-1]
+	filename := defaultPathPolicy.Normalize(parts[len(parts)-1])
 
 	// Check if filename is in the envFileNames set
-	if _, exists := envFileNames[filename]; exists {
-		return true
+	if _, exists := normalizedEnvFileNames[filename]; exists {
+		return true, "env file"
 	}
 
 	// Check if filename starts with .env.
-	if strings.HasPrefix(filename, ".env.") {
-		return true
+	if strings.HasPrefix(filename, defaultPathPolicy.Normalize(".env.")) {
+		return true, "env file"
+	}
+
+	if IsTransientEnvArtifact(filePath) {
+		return true, "transient env artifact"
+	}
+
+	return false, ""
+}
+
+// transientSuffixes are editor/backup suffixes that should be treated the
+// same as the env file they're attached to, so an in-progress edit of .env
+// doesn't leave a scannable artifact behind. Data-driven so other callers
+// wanting to skip the same set of artifacts (for reasons other than env-file
+// detection) can reuse it.
+var transientSuffixes = []string{
+	".swp", ".swo", ".swn", // vim swap files
+	"~",                    // generic tilde backup
+	".bak", ".orig", ".old", // generic backup suffixes
+}
+
+// vimNumericSuffixRegex matches vim's numeric temp-file suffix, e.g. the
+// ".12345" in ".env.12345".
+var vimNumericSuffixRegex = regexp.MustCompile(`\.\d+$`)
+
+// IsTransientEnvArtifact reports whether filePath looks like an editor swap
+// file, backup, emacs lockfile, or vim numeric temp file sitting next to an
+// env file: "foo.env.swp", ".env~", ".env.bak", "#.env#", ".env.12345".
+func IsTransientEnvArtifact(filePath string) bool {
+	normalizedPath := strings.ReplaceAll(filePath, "\\", "/")
+	parts := strings.Split(normalizedPath, "/")
+	filename := parts[len(parts)-1]
+
+	if base, ok := stripEmacsLockDecoration(filename); ok {
+		return looksLikeEnvBaseName(base)
+	}
+
+	for _, suffix := range transientSuffixes {
+		if strings.HasSuffix(filename, suffix) && looksLikeEnvBaseName(strings.TrimSuffix(filename, suffix)) {
+			return true
+		}
+	}
+
+	if loc := vimNumericSuffixRegex.FindStringIndex(filename); loc != nil {
+		if looksLikeEnvBaseName(filename[:loc[0]]) {
+			return true
+		}
 	}
 
 	return false
 }
 
+// stripEmacsLockDecoration strips emacs' auto-save ("#name#") or lock-file
+// (".#name") decoration from filename, returning the underlying name.
+func stripEmacsLockDecoration(filename string) (string, bool) {
+	if strings.HasPrefix(filename, "#") && strings.HasSuffix(filename, "#") && len(filename) > 1 {
+		return filename[1 : len(filename)-1], true
+	}
+	if strings.HasPrefix(filename, ".#") {
+		return filename[2:], true
+	}
+	return "", false
+}
+
+// looksLikeEnvBaseName reports whether name is itself a recognized env file,
+// or ends with/contains a ".env" segment, so stripping a transient suffix
+// from something like "foo.env.swp" or ".env.12345" still resolves to an
+// env-like name.
+func looksLikeEnvBaseName(name string) bool {
+	if _, exists := normalizedEnvFileNames[defaultPathPolicy.Normalize(name)]; exists {
+		return true
+	}
+
+	normalized := defaultPathPolicy.Normalize(name)
+	return strings.HasSuffix(normalized, ".env") || strings.Contains(normalized, ".env.")
+}
+
 // IsBinaryFile checks if a file has a binary extension that should be skipped.
-// Case-insensitive matching. Handles compound extensions like .min.js.
+// Case-insensitive matching. Handles compound extensions like .min.js. Also
+// consults defaultSkipMatcher so a .securityhooksignore pattern can mark
+// additional paths as binary without recompiling.
 func IsBinaryFile(filePath string) bool {
-	// Lowercase the full path for case-insensitive matching
-	lowerPath := strings.ToLower(filePath)
+	if ok, _ := isBinaryFileReason(filePath); ok {
+		return true
+	}
+	ok, _ := defaultSkipMatcher.Match(filePath)
+	return ok
+}
+
+// isBinaryFileReason is the built-in (non-user-pattern) check behind
+// IsBinaryFile, also used directly by ShouldSkip so it can report a specific
+// reason.
+func isBinaryFileReason(filePath string) (bool, string) {
+	// Normalize the full path per defaultPathPolicy (Unicode form, and case
+	// when the policy is case-insensitive) for comparison
+	normalizedPath := defaultPathPolicy.Normalize(filePath)
+
+	// A .security-hooks.json scanExtensions entry overrides the built-in
+	// skip list for this extension, so it's scanned despite looking binary.
+	if isForceScanExtension(normalizedPath) {
+		return false, ""
+	}
 
 	// Check single-dot extensions using filepath.Ext
-	ext := filepath.Ext(lowerPath)
-	if _, exists := binaryExtensions[ext]; exists {
-		return true
+	ext := filepath.Ext(normalizedPath)
+	if _, exists := normalizedBinaryExtensions[ext]; exists {
+		return true, "binary extension"
 	}
 
 	// Check compound extensions
-	for _, compoundExt := range compoundBinaryExtensions {
-		if strings.HasSuffix(lowerPath, compoundExt) {
-			return true
+	for _, compoundExt := range normalizedCompoundBinaryExtensions {
+		if strings.HasSuffix(normalizedPath, compoundExt) {
+			return true, "binary extension"
 		}
 	}
 
+	return false, ""
+}
+
+// forceScanExtensions holds extensions from a repo's .security-hooks.json
+// scanExtensions list that should be scanned despite matching the built-in
+// binary-extension skip list. Empty by default; populated once per run by
+// ApplyRepoConfig before any staged file is scanned.
+var forceScanExtensions = map[string]struct{}{}
+
+// isForceScanExtension reports whether normalizedPath ends in an extension
+// a repo's config opted back into scanning.
+func isForceScanExtension(normalizedPath string) bool {
+	for ext := range forceScanExtensions {
+		if strings.HasSuffix(normalizedPath, ext) {
+			return true
+		}
+	}
 	return false
 }
+
+// IsBinaryContent reports whether head (the first bytes of a file, typically
+// up to binaryContentSniffLen) looks binary. It first defers to IsBinaryFile's
+// extension check, then sniffs content so files with a stripped or unusual
+// extension (e.g. "payload", "libfoo") are still caught.
+func IsBinaryContent(path string, head []byte) bool {
+	if IsBinaryFile(path) {
+		return true
+	}
+
+	for _, magic := range binaryMagicNumbers {
+		if bytes.HasPrefix(head, magic) {
+			return true
+		}
+	}
+
+	nulScanTo := len(head)
+	if nulScanTo > nulScanLen {
+		nulScanTo = nulScanLen
+	}
+	if bytes.IndexByte(head[:nulScanTo], 0) >= 0 {
+		return true
+	}
+
+	contentType := http.DetectContentType(head)
+	return !strings.HasPrefix(contentType, "text/")
+}
+
+// IsBinaryFileByContent is a convenience wrapper around IsBinaryContent that
+// reads a file's leading bytes itself. Use this when only a path is
+// available; use IsBinaryContent directly when content is already in memory
+// (e.g. staged blob content read via StagedReader).
+func IsBinaryFileByContent(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = f.Close() }()
+
+	head := make([]byte, binaryContentSniffLen)
+	n, err := f.Read(head)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	return IsBinaryContent(path, head[:n]), nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import "strings"
+
+// Finding is the structured form of a single potential secret detection,
+// carrying enough detail (rule id, location, redacted snippet) to drive
+// machine-readable output formats like SARIF, on top of the plain
+// "filepath:line - description" strings the hook has always printed.
+type Finding struct {
+	RuleID   string
+	FilePath string
+	Line     int
+	Column   int
+	Message  string
+	Snippet  string
+	// LineHash is a SHA-256 hex digest of the finding's line with the
+	// matched text replaced by a canonical placeholder, used to key
+	// .secrets-baseline.json entries. Empty when it couldn't be computed
+	// (e.g. an external detector reported a line/column outside the file).
+	LineHash string
+	// SecretHash is a SHA-256 hex digest of the raw matched text itself
+	// (before redaction), used to key a .security-hooks.json allowlist
+	// entry's "secret" field so a known fixture value can be pinned
+	// exactly rather than by line or pattern. Empty wherever LineHash is.
+	SecretHash string
+	// VerifyAttempted is true when SECRET_VERIFY=1 and RuleID had a live
+	// Verifier registered (see verify.go), regardless of the outcome.
+	VerifyAttempted bool
+	// Verified is true when VerifyAttempted live-confirmed the matched text
+	// is a currently valid credential, e.g. a GitHub PAT that authenticates
+	// successfully against the GitHub API. Meaningless when VerifyAttempted
+	// is false.
+	Verified bool
+	// VerifyMeta carries optional provider metadata from a successful
+	// Verified check (e.g. the GitHub user a PAT authenticates as). Nil
+	// unless Verified.
+	VerifyMeta map[string]string
+}
+
+// redactSnippet returns a version of matched safe to embed in a report: the
+// first and last two characters are kept and everything else is replaced
+// with asterisks, so a reviewer can recognize the finding without the report
+// itself becoming a copy of the secret.
+func redactSnippet(matched string) string {
+	const keep = 2
+	if len(matched) <= keep*2 {
+		return strings.Repeat("*", len(matched))
+	}
+	return matched[:keep] + strings.Repeat("*", len(matched)-keep*2) + matched[len(matched)-keep:]
+}
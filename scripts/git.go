@@ -1,63 +1,36 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os/exec"
-	"regexp"
+	"strconv"
 	"strings"
 )
 
-var (
-	// Pre-compiled regexes for git commit detection
-	commandSeparatorRegex = regexp.MustCompile(`[;&|]+`)
-	gitCommandRegex       = regexp.MustCompile(`(?i)^(?:\S+[/\\])?git(?:\.exe)?\b`)
-	commitWordRegex       = regexp.MustCompile(`(?i)\bcommit\b`)
-)
-
-// IsGitCommitCommand checks if a command string contains a git commit operation.
-// Splits command by separators and checks each subcommand.
-func IsGitCommitCommand(command string) bool {
-	// Split command on separators: ; & |
-	subcommands := commandSeparatorRegex.Split(command, -1)
-
-	for _, subcommand := range subcommands {
-		subcommand = strings.TrimSpace(subcommand)
-		if subcommand == "" {
-			continue
-		}
-
-		// Check if subcommand starts with git (handles git, /path/to/git, git.exe)
-		if !gitCommandRegex.MatchString(subcommand) {
-			continue
-		}
-
-		// Check if the git command contains the word "commit"
-		if commitWordRegex.MatchString(subcommand) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// GetStagedFiles returns the list of staged file paths via "git diff --cached --name-only".
+// GetStagedFiles returns the list of staged file paths via
+// "git diff --cached --name-only -z". The NUL-delimited form (-z) avoids
+// ambiguity with filenames containing newlines, unlike a plain newline split.
+// This is the same "differs from HEAD" semantics goGitStagedReader.
+// ListStagedFiles mirrors for the go-git-backed path that NewStagedReader
+// actually picks in the common case.
 func GetStagedFiles() ([]string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), SubprocessTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "git", "diff", "--cached", "--name-only")
+	cmd := exec.CommandContext(ctx, "git", "diff", "--cached", "--name-only", "-z")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
 
-	// Split output by newlines and filter empty strings
-	lines := strings.Split(string(output), "\n")
 	var files []string
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			files = append(files, line)
+	for _, entry := range strings.Split(string(output), "\x00") {
+		if entry != "" {
+			files = append(files, entry)
 		}
 	}
 
@@ -77,3 +50,124 @@ func GetStagedContent(filePath string) (string, error) {
 
 	return string(output), nil
 }
+
+// CatFileBatch wraps a single long-lived "git cat-file --batch" subprocess so
+// staged blobs can be read without a fork/exec per file. Read is not safe for
+// concurrent use by multiple goroutines; each call writes a request line and
+// then consumes the matching reply off the same pipe.
+type CatFileBatch struct {
+	cancel  context.CancelFunc
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stdout  *bufio.Reader
+	maxSize int
+}
+
+// ErrBlobTooLarge is returned by CatFileBatch.Read when a blob's header
+// reports a size over the batch's configured limit. The blob's bytes are
+// drained from the pipe (so the next request stays in sync) but never
+// allocated, bounding memory use for oversized staged files.
+var ErrBlobTooLarge = errors.New("blob exceeds maximum read size")
+
+// NewCatFileBatch starts a "git cat-file --batch" process rooted at repoRoot,
+// rejecting any blob larger than MaxFileSize. The process runs for the
+// lifetime of the returned CatFileBatch, bounded by a single overall deadline
+// rather than a per-file timeout.
+func NewCatFileBatch(repoRoot string) (*CatFileBatch, error) {
+	return NewCatFileBatchWithLimit(repoRoot, MaxFileSize)
+}
+
+// NewCatFileBatchWithLimit is NewCatFileBatch with a caller-supplied maximum
+// blob size, for callers that want a different bound than MaxFileSize.
+func NewCatFileBatchWithLimit(repoRoot string, maxSize int) (*CatFileBatch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), BatchScanTimeout)
+
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "--batch")
+	cmd.Dir = repoRoot
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &CatFileBatch{
+		cancel:  cancel,
+		cmd:     cmd,
+		stdin:   stdin,
+		stdout:  bufio.NewReader(stdout),
+		maxSize: maxSize,
+	}, nil
+}
+
+// Read returns the staged (index) content of path, reusing the batch
+// process's pipe instead of spawning a new subprocess. If the blob's header
+// reports a size over b.maxSize, Read discards the payload without
+// allocating it and returns ErrBlobTooLarge.
+func (b *CatFileBatch) Read(path string) (string, error) {
+	if _, err := fmt.Fprintf(b.stdin, ":%s\n", path); err != nil {
+		return "", err
+	}
+
+	header, err := b.stdout.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	header = strings.TrimSuffix(header, "\n")
+
+	fields := strings.Fields(header)
+	if len(fields) >= 2 && fields[len(fields)-1] == "missing" {
+		return "", fmt.Errorf("path %q not found in staging area", path)
+	}
+	if len(fields) != 3 || fields[1] != "blob" {
+		return "", fmt.Errorf("unexpected cat-file --batch reply: %q", header)
+	}
+
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return "", fmt.Errorf("unexpected cat-file --batch size %q: %w", fields[2], err)
+	}
+
+	// The payload is always followed by a trailing newline, whether we
+	// read it or discard it.
+	if b.maxSize > 0 && size > b.maxSize {
+		if _, err := io.CopyN(io.Discard, b.stdout, int64(size)+1); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("%w: %q is %d bytes", ErrBlobTooLarge, path, size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(b.stdout, payload); err != nil {
+		return "", err
+	}
+
+	if _, err := b.stdout.ReadByte(); err != nil {
+		return "", err
+	}
+
+	return string(payload), nil
+}
+
+// Close shuts down the batch process and releases its resources.
+func (b *CatFileBatch) Close() error {
+	defer b.cancel()
+
+	err := b.stdin.Close()
+	if waitErr := b.cmd.Wait(); err == nil {
+		err = waitErr
+	}
+
+	return err
+}
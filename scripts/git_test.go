@@ -1,217 +1,130 @@
 package main
 
-import "testing"
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
 
 // ---------------------------------------------------------------------------
-// TestIsGitCommitCommand
+// TestCatFileBatch
 // ---------------------------------------------------------------------------
 
-func Test_IsGitCommitCommand_Cases(t *testing.T) {
-	tests := []struct {
-		name    string
-		command string
-		want    bool
-	}{
-		// Commands that SHOULD match.
-		{
-			name:    "simple git commit",
-			command: "git commit",
-			want:    true,
-		},
-		{
-			name:    "git commit with message flag",
-			command: `git commit -m "message"`,
-			want:    true,
-		},
-		{
-			name:    "git commit with add-and-message flag",
-			command: `git commit -am "message"`,
-			want:    true,
-		},
-		{
-			name:    "git commit with message and amend",
-			command: `git commit -m "fix" --amend`,
-			want:    true,
-		},
-		{
-			name:    "git commit with allow-empty",
-			command: "git commit --allow-empty",
-			want:    true,
-		},
-		{
-			name:    "git commit with signing and message",
-			command: `git commit -S "key" -m "msg"`,
-			want:    true,
-		},
-		{
-			name:    "all caps GIT COMMIT",
-			command: "GIT COMMIT",
-			want:    true,
-		},
-		{
-			name:    "mixed case Git Commit",
-			command: `Git Commit -m "msg"`,
-			want:    true,
-		},
-		{
-			name:    "random case gIT cOMMIT",
-			command: "gIT cOMMIT",
-			want:    true,
-		},
-		{
-			name:    "unix path prefix /usr/bin/git commit",
-			command: "/usr/bin/git commit",
-			want:    true,
-		},
-		{
-			name:    "windows path prefix git.exe commit",
-			command: `C:\git\git.exe commit`,
-			want:    true,
-		},
-		{
-			name:    "git with -C flag before commit",
-			command: "git -C /path/to/repo commit -m 'msg'",
-			want:    true,
-		},
-
-		// Commands that should NOT match.
-		{
-			name:    "git push",
-			command: "git push",
-			want:    false,
-		},
-		{
-			name:    "git pull",
-			command: "git pull",
-			want:    false,
-		},
-		{
-			name:    "git add",
-			command: "git add file.txt",
-			want:    false,
-		},
-		{
-			name:    "git status",
-			command: "git status",
-			want:    false,
-		},
-		{
-			name:    "commit without git prefix",
-			command: `commit -m "msg"`,
-			want:    false,
-		},
-		{
-			name:    "just commit word",
-			command: "just commit",
-			want:    false,
-		},
-		{
-			name:    "empty string",
-			command: "",
-			want:    false,
-		},
-		{
-			name:    "git without subcommand",
-			command: "git",
-			want:    false,
-		},
-		{
-			name:    "echo with git and commit words",
-			command: `echo "git is for commit"`,
-			want:    false,
-		},
-		{
-			name:    "gitcommit no word boundary",
-			command: "gitcommit",
-			want:    false,
-		},
-		{
-			name:    "mygit prefix before git",
-			command: "mygit commit",
-			want:    false,
-		},
-		{
-			name:    "git with non-commit word mycommit",
-			command: "git mycommit",
-			want:    false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := IsGitCommitCommand(tt.command)
-			if got != tt.want {
-				t.Errorf("IsGitCommitCommand(%q) = %v, want %v", tt.command, got, tt.want)
-			}
-		})
+func Test_CatFileBatch_ReadsStagedContent(t *testing.T) {
+	dir := setupGitRepo(t)
+	writeTestFile(t, dir, "staged.txt", "hello batch\n")
+	gitAdd(t, dir, "staged.txt")
+
+	batch, err := NewCatFileBatch(dir)
+	if err != nil {
+		t.Fatalf("NewCatFileBatch() error = %v", err)
+	}
+	defer func() { _ = batch.Close() }()
+
+	content, err := batch.Read("staged.txt")
+	if err != nil {
+		t.Fatalf("Read(staged.txt) error = %v", err)
+	}
+	if content != "hello batch\n" {
+		t.Errorf("Read(staged.txt) = %q, want %q", content, "hello batch\n")
 	}
 }
 
-// ---------------------------------------------------------------------------
-// Additional IsGitCommitCommand edge cases
-// ---------------------------------------------------------------------------
+func Test_CatFileBatch_MissingPath(t *testing.T) {
+	dir := setupGitRepo(t)
+
+	batch, err := NewCatFileBatch(dir)
+	if err != nil {
+		t.Fatalf("NewCatFileBatch() error = %v", err)
+	}
+	defer func() { _ = batch.Close() }()
 
-func Test_IsGitCommitCommand_CompoundCommands(t *testing.T) {
-	tests := []struct {
-		name    string
-		command string
-		want    bool
-	}{
-		{
-			name:    "git add then git commit separated by semicolon",
-			command: "git add . ; git commit -m 'msg'",
-			want:    true,
-		},
-		{
-			name:    "git add then git commit separated by &&",
-			command: "git add . && git commit -m 'msg'",
-			want:    true,
-		},
-		{
-			name:    "git add then git commit separated by ||",
-			command: "git add . || git commit -m 'msg'",
-			want:    true,
-		},
-		{
-			name:    "multiple non-commit commands separated by semicolon",
-			command: "git add . ; git status ; git diff",
-			want:    false,
-		},
-		{
-			name:    "only non-git commands with commit word",
-			command: "echo commit ; ls -la",
-			want:    false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := IsGitCommitCommand(tt.command)
-			if got != tt.want {
-				t.Errorf("IsGitCommitCommand(%q) = %v, want %v", tt.command, got, tt.want)
-			}
-		})
+	if _, err := batch.Read("does-not-exist.txt"); err == nil {
+		t.Error("Read(does-not-exist.txt) expected an error, got nil")
+	} else if !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Read(does-not-exist.txt) error = %v, want it to mention \"not found\"", err)
 	}
 }
 
-// ---------------------------------------------------------------------------
-// Benchmarks
-// ---------------------------------------------------------------------------
+func Test_CatFileBatch_MultipleReadsReuseThePipe(t *testing.T) {
+	dir := setupGitRepo(t)
+	writeTestFile(t, dir, "a.txt", "aaa\n")
+	writeTestFile(t, dir, "b.txt", "bbb\n")
+	gitAdd(t, dir, "a.txt")
+	gitAdd(t, dir, "b.txt")
 
-func Benchmark_IsGitCommitCommand_SimpleCommit(b *testing.B) {
-	for i := 0; i < b.N; i++ {
-		IsGitCommitCommand(`git commit -m "test message"`)
+	batch, err := NewCatFileBatch(dir)
+	if err != nil {
+		t.Fatalf("NewCatFileBatch() error = %v", err)
+	}
+	defer func() { _ = batch.Close() }()
+
+	if content, err := batch.Read("a.txt"); err != nil || content != "aaa\n" {
+		t.Errorf("Read(a.txt) = (%q, %v), want (%q, nil)", content, err, "aaa\n")
+	}
+	if content, err := batch.Read("b.txt"); err != nil || content != "bbb\n" {
+		t.Errorf("Read(b.txt) = (%q, %v), want (%q, nil)", content, err, "bbb\n")
 	}
 }
 
-func Benchmark_IsGitCommitCommand_NonCommit(b *testing.B) {
-	for i := 0; i < b.N; i++ {
-		IsGitCommitCommand("git push origin main")
+func Test_CatFileBatch_RejectsOversizedBlobWithoutBufferingIt(t *testing.T) {
+	dir := setupGitRepo(t)
+	writeTestFile(t, dir, "big.txt", strings.Repeat("x", 1000)+"\n")
+	writeTestFile(t, dir, "small.txt", "small\n")
+	gitAdd(t, dir, "big.txt")
+	gitAdd(t, dir, "small.txt")
+
+	batch, err := NewCatFileBatchWithLimit(dir, 100)
+	if err != nil {
+		t.Fatalf("NewCatFileBatchWithLimit() error = %v", err)
+	}
+	defer func() { _ = batch.Close() }()
+
+	if _, err := batch.Read("big.txt"); !errors.Is(err, ErrBlobTooLarge) {
+		t.Errorf("Read(big.txt) error = %v, want ErrBlobTooLarge", err)
+	}
+
+	// The oversized payload must have been fully drained from the pipe so
+	// the next request isn't desynchronized.
+	if content, err := batch.Read("small.txt"); err != nil || content != "small\n" {
+		t.Errorf("Read(small.txt) after oversized read = (%q, %v), want (%q, nil)", content, err, "small\n")
 	}
 }
 
-func Benchmark_IsGitCommitCommand_CompoundCommand(b *testing.B) {
-	for i := 0; i < b.N; i++ {
-		IsGitCommitCommand("git add . && git commit -m 'msg' && git push")
+func Test_GetStagedFiles_HandlesSpacesInFilenames(t *testing.T) {
+	dir := setupGitRepo(t)
+	writeTestFile(t, dir, "file with spaces.txt", "content\n")
+	gitAdd(t, dir, "file with spaces.txt")
+
+	files, err := gitStagedFilesInDir(t, dir)
+	if err != nil {
+		t.Fatalf("GetStagedFiles() error = %v", err)
+	}
+
+	found := false
+	for _, f := range files {
+		if f == "file with spaces.txt" {
+			found = true
+		}
 	}
+	if !found {
+		t.Errorf("GetStagedFiles() = %v, want it to contain %q", files, "file with spaces.txt")
+	}
+}
+
+// gitStagedFilesInDir runs GetStagedFiles with the process cwd temporarily
+// switched to dir, since GetStagedFiles operates on the current directory.
+func gitStagedFilesInDir(t *testing.T, dir string) ([]string, error) {
+	t.Helper()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("failed to chdir to %s: %v", dir, err)
+	}
+	defer func() { _ = os.Chdir(cwd) }()
+
+	return GetStagedFiles()
 }
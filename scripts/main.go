@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -15,9 +17,24 @@ type HookInput struct {
 	ToolInput json.RawMessage `json:"tool_input"`
 }
 
-// toolInput represents the parsed tool_input object.
+// toolInput represents the parsed tool_input object. Its fields cover both
+// Bash (Command) and Write/Edit/MultiEdit (FilePath plus whichever of
+// Content/OldString/NewString/Edits that tool sends); each handler reads
+// only the fields relevant to its tool.
 type toolInput struct {
-	Command string `json:"command"`
+	Command   string   `json:"command"`
+	FilePath  string   `json:"file_path"`
+	Content   string   `json:"content"`
+	OldString string   `json:"old_string"`
+	NewString string   `json:"new_string"`
+	Edits     []editOp `json:"edits"`
+}
+
+// editOp is one old_string/new_string pair from a MultiEdit tool_input's
+// edits array.
+type editOp struct {
+	OldString string `json:"old_string"`
+	NewString string `json:"new_string"`
 }
 
 // hookOutput represents the JSON output sent to Claude Code via stdout.
@@ -30,9 +47,18 @@ type hookSpecificOutput struct {
 	HookEventName            string `json:"hookEventName"`
 	PermissionDecision       string `json:"permissionDecision"`
 	PermissionDecisionReason string `json:"permissionDecisionReason"`
+	// Warning carries a non-blocking message, set only when
+	// SECRET_VERIFY_STRICT=1 downgraded an unverified match from a deny to
+	// an allow-with-warning (see partitionVerifiedFindings).
+	Warning string `json:"warning,omitempty"`
 }
 
 func main() {
+	if hasFlag(os.Args[1:], "--update-baseline") || hasFlag(os.Args[1:], "--audit") {
+		runBaselineMaintenance(os.Args[1:])
+		return
+	}
+
 	var input HookInput
 
 	// Parse JSON input from stdin
@@ -41,11 +67,20 @@ func main() {
 		os.Exit(ExitBlocked)
 	}
 
-	// Check if tool_name is "Bash"
-	if input.ToolName != "Bash" {
+	switch input.ToolName {
+	case "Bash":
+		handleBashTool(input)
+	case "Write", "Edit", "MultiEdit":
+		handleWriteEditTool(input)
+	default:
 		os.Exit(ExitSuccess)
 	}
+}
 
+// handleBashTool is the original PreToolUse path: it only blocks a git
+// commit, scanning the files already staged for it rather than the command
+// string itself.
+func handleBashTool(input HookInput) {
 	// Try to parse tool_input as an object
 	var ti toolInput
 	if err := json.Unmarshal(input.ToolInput, &ti); err != nil {
@@ -84,18 +119,98 @@ func main() {
 		defer func() { _ = os.Chdir(originalCwd) }()
 	}
 
-	runSecretCheck(projectRoot)
+	runSecretCheck(projectRoot, hasFullScanFlag(os.Args[1:]), sarifOutPath(os.Args[1:]), configPathFlag(os.Args[1:]))
+}
+
+// handleWriteEditTool is the PreToolUse path for Write/Edit/MultiEdit: these
+// tools write file content directly, with nothing staged in git yet, so it
+// scans the content the tool is about to introduce instead of going through
+// StagedReader.
+func handleWriteEditTool(input HookInput) {
+	var ti toolInput
+	if err := json.Unmarshal(input.ToolInput, &ti); err != nil {
+		// tool_input is not an object (could be string, null, etc.)
+		os.Exit(ExitSuccess)
+	}
+
+	projectRoot := os.Getenv("CLAUDE_PROJECT_DIR")
+	if projectRoot == "" {
+		var err error
+		projectRoot, err = os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "SECURITY: Failed to get working directory: %v\n", err)
+			os.Exit(ExitBlocked)
+		}
+	}
+
+	runToolContentCheck(projectRoot, input.ToolName, ti, sarifOutPath(os.Args[1:]), configPathFlag(os.Args[1:]))
 }
 
-// runSecretCheck orchestrates the secret scanning process.
-func runSecretCheck(projectRoot string) {
+// hasFullScanFlag reports whether a whole-file scan was requested instead of
+// the default diff-only scan, via --full-scan or SCAN_MODE=full (the flag
+// takes precedence). Useful for an initial commit where HEAD doesn't exist
+// yet and there's nothing to diff against. SCAN_MODE=diff is accepted as an
+// explicit spelling of the default and has no effect.
+func hasFullScanFlag(args []string) bool {
+	if hasFlag(args, "--full-scan") {
+		return true
+	}
+	return os.Getenv("SCAN_MODE") == "full"
+}
+
+// hasFlag reports whether flag was passed on the command line verbatim.
+func hasFlag(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// sarifOutPath returns the path a SARIF report should be written to, if the
+// caller asked for one via --sarif-out=<path> or the SECRETS_SARIF_OUT
+// environment variable. The command-line flag takes precedence. An empty
+// return value means no SARIF report was requested.
+func sarifOutPath(args []string) string {
+	const flagPrefix = "--sarif-out="
+	for _, arg := range args {
+		if strings.HasPrefix(arg, flagPrefix) {
+			return strings.TrimPrefix(arg, flagPrefix)
+		}
+	}
+	return os.Getenv("SECRETS_SARIF_OUT")
+}
+
+// configPathFlag returns the .security-hooks.json path explicitly requested
+// via --config=<path>, if any. An empty return value means RepoConfigPath
+// should fall back to its default walk-up-from-projectRoot discovery.
+func configPathFlag(args []string) string {
+	const flagPrefix = "--config="
+	for _, arg := range args {
+		if strings.HasPrefix(arg, flagPrefix) {
+			return strings.TrimPrefix(arg, flagPrefix)
+		}
+	}
+	return ""
+}
+
+// runSecretCheck orchestrates the secret scanning process. By default it
+// scans only the lines a staged file adds relative to HEAD; fullScan forces
+// it to scan each staged file's entire content instead. If sarifOut is
+// non-empty, a SARIF 2.1.0 report of every finding is written there
+// regardless of whether the commit is ultimately allowed or denied.
+func runSecretCheck(projectRoot string, fullScan bool, sarifOut string, configPath string) {
 	// Parse and filter .env file
 	envPath := filepath.Join(projectRoot, ".env")
 	envVars := ParseEnvFile(envPath)
 	secretEnvValues := FilterEnvValues(envVars)
 
-	// Get staged files
-	stagedFiles, err := GetStagedFiles()
+	// Get staged files, preferring direct object-store access over subprocess calls
+	reader := NewStagedReader(projectRoot)
+	defer func() { _ = reader.Close() }()
+
+	stagedFiles, err := reader.ListStagedFiles()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "SECURITY: Failed to get staged files: %v\n", err)
 		os.Exit(ExitBlocked)
@@ -109,60 +224,290 @@ func runSecretCheck(projectRoot string) {
 	// Compile environment value patterns once for all files
 	envPatterns := CompileEnvPatterns(secretEnvValues)
 
-	// Collect all issues
-	var allPatternIssues []string
-	var allEnvIssues []string
+	// Discover pluggable detector binaries once for all files
+	externalDetectors := DiscoverExternalDetectors(projectRoot)
+
+	// Load the baseline so previously-audited findings don't re-block the commit
+	baseline, err := LoadBaseline(BaselinePath(projectRoot))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "SECURITY: Failed to load baseline: %v\n", err)
+		os.Exit(ExitBlocked)
+	}
 
-	// Sort staged files for deterministic order
+	// Load the repo's custom rules/allowlist/scan-extension overrides, if any
+	repoConfig, err := loadCompiledRepoConfig(projectRoot, configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "SECURITY: Failed to load %s: %v\n", repoConfigFileName, err)
+		os.Exit(ExitBlocked)
+	}
+	ApplyRepoConfig(repoConfig)
+
+	// Sort staged files for deterministic order, then drop the ones we never scan
 	sort.Strings(stagedFiles)
+	filesToScan := filterFilesToScan(stagedFiles, externalDetectors)
 
-	for _, filePath := range stagedFiles {
-		// Skip binary files
-		if IsBinaryFile(filePath) {
-			continue
-		}
+	// Scan with a bounded worker pool so a large commit's per-file subprocess/IO
+	// latency is paid concurrently rather than serially; ctx bounds the whole
+	// scan so one slow file can't stall the hook indefinitely.
+	ctx, cancel := context.WithTimeout(context.Background(), BatchScanTimeout)
+	defer cancel()
 
-		// Skip .env files
-		if IsEnvFile(filePath) {
-			continue
-		}
+	_, _, allFindings := scanStagedFilesParallel(ctx, filesToScan, reader, envPatterns, fullScan, externalDetectors, baseline, repoConfig)
+	printBaselineSummary(baseline)
 
-		// Get file content from staging area
-		content, err := GetStagedContent(filePath)
-		if err != nil {
-			// Skip file if we can't read it
-			continue
+	if sarifOut != "" {
+		if err := WriteSarifReport(sarifOut, allFindings); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to write SARIF report: %v\n", err)
 		}
+	}
 
-		// Skip oversized files
-		if len(content) > MaxFileSize {
-			fmt.Fprintf(os.Stderr, "Warning: Skipping oversized staged content %s\n", filePath)
-			continue
-		}
+	denyFindings, warnFindings := partitionVerifiedFindings(allFindings)
+	patternIssues, envIssues := issuesFromFindings(denyFindings)
 
-		// Skip very small files
-		if len(content) < 10 {
-			continue
+	// If secrets were found, deny the operation
+	if len(patternIssues) > 0 || len(envIssues) > 0 {
+		output := buildDenyOutput(patternIssues, envIssues, denyFindings)
+		fmt.Println(output)
+		os.Exit(ExitBlocked)
+	}
+
+	if len(warnFindings) > 0 {
+		fmt.Println(buildWarnOutput(warnFindings))
+	}
+
+	os.Exit(ExitSuccess)
+}
+
+// newContentFromToolInput extracts the text a Write/Edit/MultiEdit
+// tool_input is about to introduce: a Write's whole content, an Edit's
+// new_string, or the concatenated new_strings of a MultiEdit's edits. Only
+// the new text is scanned, mirroring runSecretCheck's default of scanning
+// added diff lines rather than a file's entire contents.
+func newContentFromToolInput(toolName string, ti toolInput) string {
+	switch toolName {
+	case "Write":
+		return ti.Content
+	case "Edit":
+		return ti.NewString
+	case "MultiEdit":
+		var b strings.Builder
+		for _, e := range ti.Edits {
+			b.WriteString(e.NewString)
+			b.WriteString("\n")
 		}
+		return b.String()
+	default:
+		return ""
+	}
+}
+
+// runToolContentCheck scans the content a Write/Edit/MultiEdit tool_input is
+// about to write to ti.FilePath and, if sarifOut is non-empty, writes a
+// SARIF report of any findings there regardless of whether the write is
+// ultimately allowed or denied. Unlike runSecretCheck there's no git staging
+// area to read from yet, so it checks the tool_input's own new content
+// directly against the same patterns, env values, and repo config.
+func runToolContentCheck(projectRoot, toolName string, ti toolInput, sarifOut string, configPath string) {
+	if ti.FilePath == "" {
+		os.Exit(ExitSuccess)
+	}
+
+	content := newContentFromToolInput(toolName, ti)
+	if content == "" {
+		os.Exit(ExitSuccess)
+	}
+
+	if IsBinaryFile(ti.FilePath) || IsEnvFile(ti.FilePath) {
+		os.Exit(ExitSuccess)
+	}
+
+	envPath := filepath.Join(projectRoot, ".env")
+	envPatterns := CompileEnvPatterns(FilterEnvValues(ParseEnvFile(envPath)))
 
-		// Check file for secrets
-		patternIssues, envIssues := CheckFileForSecrets(filePath, content, envPatterns)
-		allPatternIssues = append(allPatternIssues, patternIssues...)
-		allEnvIssues = append(allEnvIssues, envIssues...)
+	repoConfig, err := loadCompiledRepoConfig(projectRoot, configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "SECURITY: Failed to load %s: %v\n", repoConfigFileName, err)
+		os.Exit(ExitBlocked)
 	}
+	ApplyRepoConfig(repoConfig)
+
+	if skip, _ := defaultFileFilter.ShouldSkip(ti.FilePath, content); skip {
+		os.Exit(ExitSuccess)
+	}
+
+	baseline, err := LoadBaseline(BaselinePath(projectRoot))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "SECURITY: Failed to load baseline: %v\n", err)
+		os.Exit(ExitBlocked)
+	}
+
+	_, _, findings := CheckFileForSecretsWithFindings(ti.FilePath, content, envPatterns)
+	findings = filterSuppressed(findings, baseline)
+	findings = filterAllowlisted(findings, repoConfig)
+	printBaselineSummary(baseline)
+
+	if sarifOut != "" {
+		if err := WriteSarifReport(sarifOut, findings); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to write SARIF report: %v\n", err)
+		}
+	}
+
+	denyFindings, warnFindings := partitionVerifiedFindings(findings)
+	patternIssues, envIssues := issuesFromFindings(denyFindings)
 
 	// If secrets were found, deny the operation
-	if len(allPatternIssues) > 0 || len(allEnvIssues) > 0 {
-		output := buildDenyOutput(allPatternIssues, allEnvIssues)
+	if len(patternIssues) > 0 || len(envIssues) > 0 {
+		output := buildDenyOutput(patternIssues, envIssues, denyFindings)
 		fmt.Println(output)
 		os.Exit(ExitBlocked)
 	}
 
+	if len(warnFindings) > 0 {
+		fmt.Println(buildWarnOutput(warnFindings))
+	}
+
+	os.Exit(ExitSuccess)
+}
+
+// runBaselineMaintenance handles the standalone --update-baseline and --audit
+// CLI modes. Unlike the normal hook flow, it doesn't read a PreToolUse JSON
+// payload from stdin; it scans every currently staged file's full content
+// (ignoring any existing baseline, so previously-suppressed findings are
+// re-surfaced for review) and then either rewrites the baseline outright
+// (--update-baseline) or walks the operator through marking each new finding
+// as a real secret or not (--audit). Either way it exits ExitSuccess: these
+// flags maintain the baseline, they don't gate a commit.
+func runBaselineMaintenance(args []string) {
+	projectRoot := os.Getenv("CLAUDE_PROJECT_DIR")
+	if projectRoot == "" {
+		var err error
+		projectRoot, err = os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "SECURITY: Failed to get working directory: %v\n", err)
+			os.Exit(ExitBlocked)
+		}
+	}
+
+	baselinePath := BaselinePath(projectRoot)
+	existing, err := LoadBaseline(baselinePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "SECURITY: Failed to load baseline: %v\n", err)
+		os.Exit(ExitBlocked)
+	}
+
+	findings, err := collectAllFindings(projectRoot, configPathFlag(args))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "SECURITY: Failed to scan staged files: %v\n", err)
+		os.Exit(ExitBlocked)
+	}
+
+	entries := BuildBaselineEntries(findings, existing)
+
+	if hasFlag(args, "--audit") {
+		entries, err = AuditBaseline(baselinePath, entries, bufio.NewScanner(os.Stdin), os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "SECURITY: Failed to audit baseline: %v\n", err)
+			os.Exit(ExitBlocked)
+		}
+		fmt.Printf("Audited %d finding(s); baseline written to %s\n", len(entries), baselinePath)
+		os.Exit(ExitSuccess)
+	}
+
+	if err := WriteBaseline(baselinePath, entries); err != nil {
+		fmt.Fprintf(os.Stderr, "SECURITY: Failed to write baseline: %v\n", err)
+		os.Exit(ExitBlocked)
+	}
+	fmt.Printf("Wrote %d finding(s) to %s\n", len(entries), baselinePath)
 	os.Exit(ExitSuccess)
 }
 
+// collectAllFindings runs a full-content scan (built-in patterns plus any
+// external detectors) over every staged file in projectRoot, without
+// applying the existing baseline, so --update-baseline/--audit always see
+// the complete current set of findings.
+func collectAllFindings(projectRoot, configPath string) ([]Finding, error) {
+	reader := NewStagedReader(projectRoot)
+	defer func() { _ = reader.Close() }()
+
+	stagedFiles, err := reader.ListStagedFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staged files: %w", err)
+	}
+	if len(stagedFiles) == 0 {
+		return nil, nil
+	}
+
+	envPath := filepath.Join(projectRoot, ".env")
+	envPatterns := CompileEnvPatterns(FilterEnvValues(ParseEnvFile(envPath)))
+
+	externalDetectors := DiscoverExternalDetectors(projectRoot)
+
+	repoConfig, err := loadCompiledRepoConfig(projectRoot, configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", repoConfigFileName, err)
+	}
+	ApplyRepoConfig(repoConfig)
+
+	sort.Strings(stagedFiles)
+	filesToScan := filterFilesToScan(stagedFiles, externalDetectors)
+
+	ctx, cancel := context.WithTimeout(context.Background(), BatchScanTimeout)
+	defer cancel()
+
+	_, _, findings := scanStagedFilesParallel(ctx, filesToScan, reader, envPatterns, true, externalDetectors, nil, repoConfig)
+	return findings, nil
+}
+
+// printBaselineSummary prints a one-line stderr summary of how many
+// findings baseline silently suppressed this run, if any. Baseline-accepted
+// findings are never shown individually, but this keeps them from being
+// forgotten entirely, matching .secrets-baseline.json's role as a list of
+// reviewed exceptions rather than a blind allowlist.
+func printBaselineSummary(baseline *Baseline) {
+	if n := baseline.SuppressedCount(); n > 0 {
+		fmt.Fprintf(os.Stderr, "Baseline suppressed %d previously-accepted finding(s) (see %s)\n", n, baselineFileName)
+	}
+}
+
+// loadCompiledRepoConfig loads and compiles the .security-hooks.json found
+// for projectRoot (or at configPath, if explicitly given via --config), if
+// present. A missing file yields a nil, no-op config rather than an error.
+func loadCompiledRepoConfig(projectRoot, configPath string) (*CompiledRepoConfig, error) {
+	cfg, err := LoadRepoConfig(RepoConfigPath(projectRoot, configPath))
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, nil
+	}
+	return cfg.Compile(), nil
+}
+
+// partitionVerifiedFindings splits findings into ones that should still
+// block the tool call and ones that should only warn. Without
+// SECRET_VERIFY_STRICT=1, everything blocks, matching the hook's
+// longstanding default. With it set, a finding whose RuleID had a live
+// Verifier registered but which didn't check out (VerifyAttempted but not
+// Verified) is downgraded to a warning instead of a block; findings with no
+// registered Verifier are unaffected, since they were never put to the live
+// test in the first place.
+func partitionVerifiedFindings(findings []Finding) (deny, warn []Finding) {
+	if !VerifyStrict() {
+		return findings, nil
+	}
+
+	for _, f := range findings {
+		if f.VerifyAttempted && !f.Verified {
+			warn = append(warn, f)
+			continue
+		}
+		deny = append(deny, f)
+	}
+	return deny, warn
+}
+
 // buildDenyOutput creates the JSON output for denying a git commit due to detected secrets.
-func buildDenyOutput(patternIssues, envIssues []string) string {
+func buildDenyOutput(patternIssues, envIssues []string, findings []Finding) string {
 	var reasonParts []string
 
 	reasonParts = append(reasonParts, "SECURITY WARNING: Potential secrets detected in staged files!")
@@ -194,6 +539,14 @@ func buildDenyOutput(patternIssues, envIssues []string) string {
 		reasonParts = append(reasonParts, "")
 	}
 
+	if verifiedIssues := verifiedFindingIssues(findings); len(verifiedIssues) > 0 {
+		reasonParts = append(reasonParts, "Live-verified (SECRET_VERIFY=1 confirmed these are currently valid credentials):")
+		for _, issue := range verifiedIssues {
+			reasonParts = append(reasonParts, "  - "+issue)
+		}
+		reasonParts = append(reasonParts, "")
+	}
+
 	reasonParts = append(reasonParts, "Please remove secrets before committing.")
 
 	if len(envIssues) > 0 {
@@ -221,3 +574,64 @@ func buildDenyOutput(patternIssues, envIssues []string) string {
 
 	return string(jsonBytes)
 }
+
+// verifiedFindingIssues renders the subset of findings that SECRET_VERIFY=1
+// live-confirmed as currently valid credentials, sorted for consistent
+// output, with any provider metadata appended.
+func verifiedFindingIssues(findings []Finding) []string {
+	var issues []string
+	for _, f := range findings {
+		if !f.Verified {
+			continue
+		}
+		issue := formatIssue(f.FilePath, f.Line, fmt.Sprintf("[%s] confirmed live", detectorLabel(f.RuleID)))
+		if len(f.VerifyMeta) > 0 {
+			keys := make([]string, 0, len(f.VerifyMeta))
+			for k := range f.VerifyMeta {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			meta := make([]string, 0, len(keys))
+			for _, k := range keys {
+				meta = append(meta, k+"="+f.VerifyMeta[k])
+			}
+			issue += " (" + strings.Join(meta, ", ") + ")"
+		}
+		issues = append(issues, issue)
+	}
+	sort.Strings(issues)
+	return issues
+}
+
+// buildWarnOutput creates the JSON output for allowing a tool call despite
+// unverified findings, used only when SECRET_VERIFY_STRICT=1 downgraded
+// them via partitionVerifiedFindings. The call is allowed (exit code
+// ExitSuccess handles that; this only supplies a warning for the
+// transcript) rather than denied, since strict mode exists precisely to
+// stop blocking on matches that didn't check out live.
+func buildWarnOutput(warnFindings []Finding) string {
+	patternIssues, envIssues := issuesFromFindings(warnFindings)
+	all := append(append([]string{}, patternIssues...), envIssues...)
+	sort.Strings(all)
+
+	var reasonParts []string
+	reasonParts = append(reasonParts, "SECRET_VERIFY_STRICT: the following matches did not verify as live credentials and were allowed through as a warning instead of blocked:")
+	for _, issue := range all {
+		reasonParts = append(reasonParts, "  - "+issue)
+	}
+
+	output := hookOutput{
+		HookSpecificOutput: hookSpecificOutput{
+			HookEventName: "PreToolUse",
+			Warning:       strings.Join(reasonParts, "\n"),
+		},
+	}
+
+	jsonBytes, err := json.Marshal(output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "SECURITY: Failed to marshal JSON output: %v\n", err)
+		os.Exit(ExitBlocked)
+	}
+
+	return string(jsonBytes)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// scanResult holds the issues found while scanning a single staged file.
+type scanResult struct {
+	patternIssues []string
+	envIssues     []string
+	findings      []Finding
+}
+
+// scanStagedFilesParallel scans files with a bounded worker pool instead of
+// sequentially, so the per-file blob-read/scan cost on a large commit is
+// paid concurrently rather than serially. The staged diff itself is fetched
+// once up front via AllDiffHunks rather than once per file. Worker count
+// defaults to runtime.NumCPU(), overridable via SCAN_CONCURRENCY, and capped
+// by the number of files to scan. ctx bounds the whole scan so one slow
+// file can't stall the hook past the caller's deadline. Results are sorted
+// before returning so output stays deterministic regardless of completion
+// order.
+func scanStagedFilesParallel(ctx context.Context, files []string, reader StagedReader, envPatterns map[string]CompiledEnvPattern, fullScan bool, detectors []ExternalDetector, baseline *Baseline, repoConfig *CompiledRepoConfig) ([]string, []string, []Finding) {
+	if len(files) == 0 {
+		return nil, nil, nil
+	}
+
+	workerCount := scanWorkerCount()
+	if workerCount > len(files) {
+		workerCount = len(files)
+	}
+
+	// Computed once for the whole scan rather than once per file: see
+	// AllDiffHunks. diffErr, if non-nil (e.g. no HEAD to diff against yet),
+	// makes every worker fall back to a full-file scan the same way a
+	// per-file DiffHunks failure used to.
+	var diffHunks map[string][]AddedHunk
+	var diffErr error
+	if !fullScan {
+		diffHunks, diffErr = AllDiffHunks()
+	}
+
+	paths := make(chan string)
+	results := make(chan scanResult)
+
+	var wg sync.WaitGroup
+	wg.Add(workerCount)
+	for i := 0; i < workerCount; i++ {
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				patternIssues, envIssues, findings := scanStagedFile(path, reader, envPatterns, fullScan, detectors, baseline, repoConfig, diffHunks, diffErr)
+				select {
+				case results <- scanResult{patternIssues, envIssues, findings}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(paths)
+		for _, f := range files {
+			select {
+			case paths <- f:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var allPatternIssues, allEnvIssues []string
+	var allFindings []Finding
+	for res := range results {
+		allPatternIssues = append(allPatternIssues, res.patternIssues...)
+		allEnvIssues = append(allEnvIssues, res.envIssues...)
+		allFindings = append(allFindings, res.findings...)
+	}
+
+	sort.Strings(allPatternIssues)
+	sort.Strings(allEnvIssues)
+	sort.Slice(allFindings, func(i, j int) bool {
+		if allFindings[i].FilePath != allFindings[j].FilePath {
+			return allFindings[i].FilePath < allFindings[j].FilePath
+		}
+		if allFindings[i].Line != allFindings[j].Line {
+			return allFindings[i].Line < allFindings[j].Line
+		}
+		if allFindings[i].RuleID != allFindings[j].RuleID {
+			return allFindings[i].RuleID < allFindings[j].RuleID
+		}
+		return allFindings[i].Column < allFindings[j].Column
+	})
+
+	return allPatternIssues, allEnvIssues, allFindings
+}
+
+// scanWorkerCount returns the number of workers scanStagedFilesParallel
+// should start: runtime.NumCPU(), or the SCAN_CONCURRENCY override if it's
+// set to a valid positive integer.
+func scanWorkerCount() int {
+	if v := os.Getenv("SCAN_CONCURRENCY"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return runtime.NumCPU()
+}
+
+// scanStagedFile reads and scans a single staged file, mirroring the
+// per-file logic each worker runs: skip oversized/too-small content, skip a
+// file defaultFileFilter rejects outright (extension, path, size, or content
+// blacklisted via .security-hooks.json's fileFilter section), then scan only
+// the added diff lines unless fullScan was requested or diffErr indicates
+// there was no HEAD to diff against. diffHunks and diffErr come from a
+// single AllDiffHunks call shared by every worker in this scan, rather than
+// a per-file git invocation. detectors matching the file also run, even
+// over content the built-in scan skips, if a detector's manifest opted into
+// handlesBinary. Findings already recorded in baseline as audited, or
+// matching a .security-hooks.json allowlist entry, are dropped before the
+// issue strings are built.
+//
+// Each file's content is read into memory as one blob (bounded by
+// MaxFileSize) rather than streamed line-by-line: the private-key pattern,
+// and multi-line secrets generally, have to see more than one line at a
+// time to match, so a bufio.Scanner line cursor would miss them.
+func scanStagedFile(filePath string, reader StagedReader, envPatterns map[string]CompiledEnvPattern, fullScan bool, detectors []ExternalDetector, baseline *Baseline, repoConfig *CompiledRepoConfig, diffHunks map[string][]AddedHunk, diffErr error) ([]string, []string, []Finding) {
+	content, err := reader.ReadStagedFile(filePath)
+	if err != nil {
+		if errors.Is(err, ErrBlobTooLarge) {
+			fmt.Fprintf(os.Stderr, "Warning: Skipping oversized staged content %s\n", filePath)
+		}
+		return nil, nil, nil
+	}
+
+	if len(content) > MaxFileSize {
+		fmt.Fprintf(os.Stderr, "Warning: Skipping oversized staged content %s\n", filePath)
+		return nil, nil, nil
+	}
+	if len(content) < 10 {
+		return nil, nil, nil
+	}
+
+	var findings []Finding
+
+	if skip, _ := defaultFileFilter.ShouldSkip(filePath, content); skip {
+		return nil, nil, nil
+	}
+
+	builtinSkip := IsBinaryFile(filePath) || IsEnvFile(filePath)
+	if !builtinSkip {
+		if fullScan {
+			_, _, findings = CheckFileForSecretsWithFindings(filePath, content, envPatterns)
+		} else if diffErr == nil {
+			_, _, findings = ScanAddedHunksWithFindings(filePath, diffHunks[filePath], envPatterns)
+		} else {
+			_, _, findings = CheckFileForSecretsWithFindings(filePath, content, envPatterns)
+		}
+	}
+
+	for _, d := range matchingDetectors(detectors, filePath) {
+		if builtinSkip && !d.HandlesBinary {
+			continue
+		}
+		extFindings, err := runExternalDetector(d, filePath, content)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: external detector %q failed on %s: %v\n", d.ID, filePath, err)
+			continue
+		}
+		findings = append(findings, extFindings...)
+	}
+
+	findings = filterSuppressed(findings, baseline)
+	findings = filterAllowlisted(findings, repoConfig)
+	patternIssues, envIssues := issuesFromFindings(findings)
+	return patternIssues, envIssues, findings
+}
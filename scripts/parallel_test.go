@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestScanStagedFilesParallel
+// ---------------------------------------------------------------------------
+
+func Test_ScanStagedFilesParallel_FindsIssuesAcrossFiles(t *testing.T) {
+	dir := setupGitRepo(t)
+
+	writeTestFile(t, dir, "clean.py", "def hello():\n    print('hi')\n")
+	writeTestFile(t, dir, "secret1.py", "api_key = 'sk-ant-"+strings.Repeat("a", 30)+"'\n")
+	writeTestFile(t, dir, "secret2.py", "token = 'ghp_"+strings.Repeat("B", 36)+"'\n")
+	gitAdd(t, dir, "clean.py")
+	gitAdd(t, dir, "secret1.py")
+	gitAdd(t, dir, "secret2.py")
+
+	reader := NewStagedReader(dir)
+	defer func() { _ = reader.Close() }()
+
+	patternIssues, _, _ := scanStagedFilesParallel(
+		context.Background(),
+		[]string{"clean.py", "secret1.py", "secret2.py"},
+		reader,
+		map[string]CompiledEnvPattern{},
+		true, // full-scan: these files have no HEAD to diff against yet
+		nil,
+		nil,
+		nil,
+	)
+
+	if len(patternIssues) < 2 {
+		t.Fatalf("scanStagedFilesParallel() found %d issues, want >= 2: %v", len(patternIssues), patternIssues)
+	}
+	assertContainsSubstring(t, patternIssues, "secret1.py")
+	assertContainsSubstring(t, patternIssues, "secret2.py")
+	assertNotContainsSubstring(t, patternIssues, "clean.py")
+}
+
+func Test_ScanStagedFilesParallel_ResultsAreSorted(t *testing.T) {
+	dir := setupGitRepo(t)
+
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("secret%d.py", i)
+		writeTestFile(t, dir, name, "token = 'ghp_"+strings.Repeat("C", 36)+"'\n")
+		gitAdd(t, dir, name)
+	}
+
+	reader := NewStagedReader(dir)
+	defer func() { _ = reader.Close() }()
+
+	files := []string{"secret0.py", "secret1.py", "secret2.py", "secret3.py", "secret4.py"}
+	patternIssues, _, _ := scanStagedFilesParallel(context.Background(), files, reader, map[string]CompiledEnvPattern{}, true, nil, nil, nil)
+
+	for i := 1; i < len(patternIssues); i++ {
+		if patternIssues[i-1] > patternIssues[i] {
+			t.Errorf("scanStagedFilesParallel() results not sorted: %q came before %q", patternIssues[i-1], patternIssues[i])
+		}
+	}
+}
+
+func Test_ScanStagedFilesParallel_NoFiles(t *testing.T) {
+	dir := setupGitRepo(t)
+	reader := NewStagedReader(dir)
+	defer func() { _ = reader.Close() }()
+
+	patternIssues, envIssues, findings := scanStagedFilesParallel(context.Background(), nil, reader, map[string]CompiledEnvPattern{}, true, nil, nil, nil)
+	if patternIssues != nil || envIssues != nil || findings != nil {
+		t.Errorf("scanStagedFilesParallel(nil files) = (%v, %v, %v), want (nil, nil, nil)", patternIssues, envIssues, findings)
+	}
+}
+
+func Test_ScanStagedFilesParallel_FindingsSortedByPathLineRuleID(t *testing.T) {
+	dir := setupGitRepo(t)
+
+	writeTestFile(t, dir, "mixed.py", strings.Join([]string{
+		"token = 'ghp_" + strings.Repeat("D", 36) + "'",
+		"api_key = 'sk-ant-" + strings.Repeat("e", 30) + "'",
+		"",
+	}, "\n"))
+	gitAdd(t, dir, "mixed.py")
+
+	reader := NewStagedReader(dir)
+	defer func() { _ = reader.Close() }()
+
+	_, _, findings := scanStagedFilesParallel(context.Background(), []string{"mixed.py"}, reader, map[string]CompiledEnvPattern{}, true, nil, nil, nil)
+
+	for i := 1; i < len(findings); i++ {
+		prev, cur := findings[i-1], findings[i]
+		if prev.FilePath != cur.FilePath {
+			if prev.FilePath > cur.FilePath {
+				t.Errorf("findings not sorted by path: %q came before %q", prev.FilePath, cur.FilePath)
+			}
+			continue
+		}
+		if prev.Line != cur.Line {
+			if prev.Line > cur.Line {
+				t.Errorf("findings not sorted by line within %s: %d came before %d", prev.FilePath, prev.Line, cur.Line)
+			}
+			continue
+		}
+		if prev.RuleID > cur.RuleID {
+			t.Errorf("findings not sorted by ruleID within %s:%d: %q came before %q", prev.FilePath, prev.Line, prev.RuleID, cur.RuleID)
+		}
+	}
+}
+
+func Test_ScanWorkerCount_HonorsEnvOverride(t *testing.T) {
+	t.Setenv("SCAN_CONCURRENCY", "3")
+	if got := scanWorkerCount(); got != 3 {
+		t.Errorf("scanWorkerCount() = %d with SCAN_CONCURRENCY=3, want 3", got)
+	}
+
+	t.Setenv("SCAN_CONCURRENCY", "not-a-number")
+	if got := scanWorkerCount(); got != runtime.NumCPU() {
+		t.Errorf("scanWorkerCount() = %d with an invalid SCAN_CONCURRENCY, want runtime.NumCPU() = %d", got, runtime.NumCPU())
+	}
+
+	t.Setenv("SCAN_CONCURRENCY", "0")
+	if got := scanWorkerCount(); got != runtime.NumCPU() {
+		t.Errorf("scanWorkerCount() = %d with SCAN_CONCURRENCY=0, want runtime.NumCPU() = %d", got, runtime.NumCPU())
+	}
+}
+
+// Test_ScanStagedFilesParallel_ConcurrentWorkersShareDetectorStateSafely
+// scans enough files with enough workers that shared read-only state (the
+// package-level activeSecretPatterns slice and defaultEntropyScanner) is
+// actually touched from multiple goroutines at once; run with -race, this
+// is what would catch a detector accidentally holding mutable state.
+func Test_ScanStagedFilesParallel_ConcurrentWorkersShareDetectorStateSafely(t *testing.T) {
+	dir := setupGitRepo(t)
+
+	const count = 64
+	files := make([]string, count)
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("race%03d.py", i)
+		files[i] = name
+		content := "print('hello')\n"
+		if i%3 == 0 {
+			content = "cred = 'ghp_" + strings.Repeat("F", 36) + "'\n"
+		}
+		writeTestFile(t, dir, name, content)
+		gitAdd(t, dir, name)
+	}
+
+	t.Setenv("SCAN_CONCURRENCY", "8")
+	reader := NewStagedReader(dir)
+	defer func() { _ = reader.Close() }()
+
+	patternIssues, _, findings := scanStagedFilesParallel(context.Background(), files, reader, map[string]CompiledEnvPattern{}, true, nil, nil, nil)
+
+	wantIssues := (count + 2) / 3
+	if len(patternIssues) != wantIssues || len(findings) != wantIssues {
+		t.Errorf("scanStagedFilesParallel() found %d issues / %d findings, want %d of each", len(patternIssues), len(findings), wantIssues)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Benchmarks - 200 synthetic staged files
+// ---------------------------------------------------------------------------
+
+// runGitForBenchmark runs a git subcommand in dir, failing the benchmark on error.
+func runGitForBenchmark(b *testing.B, dir string, args ...string) {
+	b.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		b.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+// setupManyStagedFiles stages count synthetic Go-ish files, a handful of
+// which contain a detectable secret, and returns the repo dir plus the file list.
+func setupManyStagedFiles(b *testing.B, count int) (string, []string) {
+	b.Helper()
+
+	dir := b.TempDir()
+	runGitForBenchmark(b, dir, "init")
+	runGitForBenchmark(b, dir, "config", "user.email", "bench@test.com")
+	runGitForBenchmark(b, dir, "config", "user.name", "Bench")
+
+	files := make([]string, count)
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("file%03d.py", i)
+		files[i] = name
+
+		content := strings.Repeat("print('hello world')\n", 20)
+		if i%25 == 0 {
+			content += "api_key = 'sk-ant-" + strings.Repeat("x", 30) + "'\n"
+		}
+
+		if err := os.WriteFile(dir+"/"+name, []byte(content), 0o644); err != nil {
+			b.Fatalf("failed to write %s: %v", name, err)
+		}
+		runGitForBenchmark(b, dir, "add", name)
+	}
+
+	return dir, files
+}
+
+func Benchmark_ScanStagedFilesParallel_200Files(b *testing.B) {
+	dir, files := setupManyStagedFiles(b, 200)
+	reader := NewStagedReader(dir)
+	defer func() { _ = reader.Close() }()
+	envPatterns := map[string]CompiledEnvPattern{}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scanStagedFilesParallel(context.Background(), files, reader, envPatterns, true, nil, nil, nil)
+	}
+}
+
+func Benchmark_ScanStagedFilesParallel_1000Files(b *testing.B) {
+	dir, files := setupManyStagedFiles(b, 1000)
+	reader := NewStagedReader(dir)
+	defer func() { _ = reader.Close() }()
+	envPatterns := map[string]CompiledEnvPattern{}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scanStagedFilesParallel(context.Background(), files, reader, envPatterns, true, nil, nil, nil)
+	}
+}
+
+func Benchmark_ScanStagedFilesSequential_200Files(b *testing.B) {
+	dir, files := setupManyStagedFiles(b, 200)
+	reader := NewStagedReader(dir)
+	defer func() { _ = reader.Close() }()
+	envPatterns := map[string]CompiledEnvPattern{}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var allPatternIssues, allEnvIssues []string
+		for _, f := range files {
+			p, e, _ := scanStagedFile(f, reader, envPatterns, true, nil, nil, nil, nil, nil)
+			allPatternIssues = append(allPatternIssues, p...)
+			allEnvIssues = append(allEnvIssues, e...)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"runtime"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// PathPolicy controls how paths are normalized before comparison, so the
+// same logical path can't slip past a check just because it was committed
+// with different casing or a different Unicode normalization form than the
+// one used in our comparison tables.
+type PathPolicy struct {
+	CaseSensitive    bool
+	NormalizeUnicode bool
+}
+
+// defaultPathPolicy is auto-detected from GOOS: Windows and macOS default to
+// case-insensitive filesystems (mirroring the escaped-path reasoning in
+// golang.org/x/mod/module, where two casings of the same path must never be
+// treated as distinct), so path comparisons there must fold case.
+var defaultPathPolicy = newPathPolicyForGOOS(runtime.GOOS)
+
+// newPathPolicyForGOOS returns the PathPolicy that should be used by default
+// on the given GOOS value.
+func newPathPolicyForGOOS(goos string) PathPolicy {
+	switch goos {
+	case "windows", "darwin":
+		return PathPolicy{CaseSensitive: false, NormalizeUnicode: true}
+	default:
+		return PathPolicy{CaseSensitive: true, NormalizeUnicode: true}
+	}
+}
+
+// Normalize returns s in the form comparisons should use under this policy:
+// NFC-normalized (if enabled) and lowercased (if case-insensitive).
+func (p PathPolicy) Normalize(s string) string {
+	if p.NormalizeUnicode {
+		s = norm.NFC.String(s)
+	}
+	if !p.CaseSensitive {
+		s = strings.ToLower(s)
+	}
+	return s
+}
+
+// normalizedEnvFileNames and normalizedBinaryExtensions/
+// normalizedCompoundBinaryExtensions hold defaultPathPolicy-normalized copies
+// of the comparison tables in patterns.go, built once at init so lookups
+// never re-normalize the same literal table entry per file scanned.
+var (
+	normalizedEnvFileNames            map[string]struct{}
+	normalizedBinaryExtensions        map[string]struct{}
+	normalizedCompoundBinaryExtensions []string
+)
+
+func init() {
+	normalizedEnvFileNames = make(map[string]struct{}, len(envFileNames))
+	for name := range envFileNames {
+		normalizedEnvFileNames[defaultPathPolicy.Normalize(name)] = struct{}{}
+	}
+
+	normalizedBinaryExtensions = make(map[string]struct{}, len(binaryExtensions))
+	for ext := range binaryExtensions {
+		normalizedBinaryExtensions[defaultPathPolicy.Normalize(ext)] = struct{}{}
+	}
+
+	normalizedCompoundBinaryExtensions = make([]string, len(compoundBinaryExtensions))
+	for i, ext := range compoundBinaryExtensions {
+		normalizedCompoundBinaryExtensions[i] = defaultPathPolicy.Normalize(ext)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import "testing"
+
+func Test_NewPathPolicyForGOOS_Cases(t *testing.T) {
+	tests := []struct {
+		name              string
+		goos              string
+		wantCaseSensitive bool
+	}{
+		{name: "windows defaults case-insensitive", goos: "windows", wantCaseSensitive: false},
+		{name: "darwin defaults case-insensitive", goos: "darwin", wantCaseSensitive: false},
+		{name: "linux defaults case-sensitive", goos: "linux", wantCaseSensitive: true},
+		{name: "unknown goos defaults case-sensitive", goos: "plan9", wantCaseSensitive: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newPathPolicyForGOOS(tt.goos)
+			if got.CaseSensitive != tt.wantCaseSensitive {
+				t.Errorf("newPathPolicyForGOOS(%q).CaseSensitive = %v, want %v", tt.goos, got.CaseSensitive, tt.wantCaseSensitive)
+			}
+			if !got.NormalizeUnicode {
+				t.Errorf("newPathPolicyForGOOS(%q).NormalizeUnicode = false, want true", tt.goos)
+			}
+		})
+	}
+}
+
+func Test_PathPolicy_Normalize_Cases(t *testing.T) {
+	// nfdName and nfcName spell the same filename using the two different
+	// Unicode normalization forms for the accented character "e-acute":
+	// nfdName uses "e" + a combining acute accent (U+0065 U+0301), nfcName
+	// uses the single precomposed code point (U+00E9).
+	nfdName := "caf" + "e\u0301" + ".env"
+	nfcName := "caf" + "\u00e9" + ".env"
+
+	tests := []struct {
+		name   string
+		policy PathPolicy
+		input  string
+		want   string
+	}{
+		{
+			name:   "case-sensitive policy leaves case alone",
+			policy: PathPolicy{CaseSensitive: true, NormalizeUnicode: false},
+			input:  "Image.PNG",
+			want:   "Image.PNG",
+		},
+		{
+			name:   "case-insensitive policy lowercases",
+			policy: PathPolicy{CaseSensitive: false, NormalizeUnicode: false},
+			input:  "Image.PNG",
+			want:   "image.png",
+		},
+		{
+			name:   "unicode normalization composes NFD into NFC",
+			policy: PathPolicy{CaseSensitive: true, NormalizeUnicode: true},
+			input:  nfdName,
+			want:   nfcName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Normalize(tt.input)
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_IsEnvFile_CaseInsensitivePolicy(t *testing.T) {
+	originalPolicy := defaultPathPolicy
+	defaultPathPolicy = newPathPolicyForGOOS("windows")
+	t.Cleanup(func() { defaultPathPolicy = originalPolicy })
+
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{name: "uppercase .ENV", filePath: ".ENV", want: true},
+		{name: "mixed case .Env.Production", filePath: ".Env.Production", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsEnvFile(tt.filePath)
+			if got != tt.want {
+				t.Errorf("IsEnvFile(%q) = %v, want %v", tt.filePath, got, tt.want)
+			}
+		})
+	}
+}
@@ -12,14 +12,53 @@ const (
 	MinSecretLength   = 8
 	MaxFileSize       = 10 * 1024 * 1024 // 10MB
 	SubprocessTimeout = 30 * time.Second
+	BatchScanTimeout  = 2 * time.Minute // overall deadline for a CatFileBatch-backed scan
 )
 
 // SecretPattern represents a compiled regex pattern with its description.
 type SecretPattern struct {
 	Pattern     *regexp.Regexp
 	Description string
+	RuleID      string // stable identifier, e.g. for a SARIF report's ruleId
+	// PathGlob, if non-empty, restricts the pattern to files whose base
+	// name matches it (see ExternalDetector.Matches). Empty for every
+	// built-in pattern; only a .security-hooks.json custom rule sets it.
+	PathGlob string
+	// MinEntropy, if non-zero, requires the text captured by EntropyGroup to
+	// have Shannon entropy at or above this threshold before a match is
+	// reported, cutting false positives on low-randomness values like
+	// "secret=changeme123456" that satisfy the regex but aren't credentials.
+	MinEntropy float64
+	// EntropyGroup is the Pattern capture group MinEntropy is measured
+	// against; 0 means the whole match. Ignored when MinEntropy is 0.
+	EntropyGroup int
+	// MultiLine, if true, means Pattern only matches a block's opening
+	// header (e.g. a PEM "-----BEGIN ... PRIVATE KEY-----" line); the
+	// scanner then looks for Terminator later in the file and validates
+	// everything in between rather than reporting on the header alone. See
+	// multiLineFindings in privatekey.go.
+	MultiLine bool
+	// Terminator matches the line that closes a MultiLine block. Ignored
+	// unless MultiLine is true.
+	Terminator *regexp.Regexp
+	// Keywords, if non-empty, are cheap ASCII substrings Pattern's match
+	// always contains (e.g. "ghp_", "-----begin"). checkContentForSecrets
+	// runs every pattern's Keywords through a single Aho-Corasick pass over
+	// the scanned content (see prefilter.go) and only evaluates Pattern's
+	// regex when at least one turns up, so the full regex set no longer
+	// runs unconditionally on content that can't possibly match. A pattern
+	// with no good short anchor (e.g. the Discord token, which has none)
+	// leaves this empty and is always evaluated.
+	Keywords []string
 }
 
+// Rule IDs for the two detectors that aren't a fixed SecretPattern: hardcoded
+// .env values and the entropy-based scanner.
+const (
+	HardcodedEnvValueRuleID = "hardcoded-env-value"
+	HighEntropyStringRuleID = "high-entropy-string"
+)
+
 // skipValues contains common non-secret values to skip (all lowercase).
 var skipValues = map[string]struct{}{
 	"true":        {},
@@ -92,49 +131,73 @@ var envFileNames = map[string]struct{}{
 	".env.example":     {},
 }
 
-// secretPatterns contains all pre-compiled secret detection patterns.
+// secretPatterns contains all pre-compiled secret detection patterns. Every
+// built-in pattern applies to all files, so PathGlob is left unset; only a
+// .security-hooks.json custom rule sets it.
 var secretPatterns = []SecretPattern{
-	// Generic secrets
-	{regexp.MustCompile(`(?i)(secret|token)\s*[:=]\s*['"]?[a-zA-Z0-9_\-]{20,}`), "secret/token"},
+	// Generic secrets. The value itself (group 2) must also clear
+	// GenericEntropyThreshold, so a low-entropy placeholder like
+	// "secret=changeme123456" doesn't report.
+	{Pattern: regexp.MustCompile(`(?i)(secret|token)\s*[:=]\s*['"]?([a-zA-Z0-9_\-]{20,})`), Description: "secret/token", RuleID: "generic-secret-token", MinEntropy: GenericEntropyThreshold, EntropyGroup: 2, Keywords: []string{"secret", "token"}},
 	// AWS
-	{regexp.MustCompile(`(?i)(aws_access_key_id|aws_secret_access_key)\s*[:=]`), "AWS credentials"},
-	{regexp.MustCompile(`AKIA[0-9A-Z]{16}`), "AWS Access Key ID"},
+	{Pattern: regexp.MustCompile(`(?i)(aws_access_key_id|aws_secret_access_key)\s*[:=]`), Description: "AWS credentials", RuleID: "aws-credentials", Keywords: []string{"aws_access_key_id", "aws_secret_access_key"}},
+	{Pattern: regexp.MustCompile(`AKIA[0-9A-Z]{16}`), Description: "AWS Access Key ID", RuleID: "aws-access-key-id", Keywords: []string{"akia"}},
+	{Pattern: regexp.MustCompile(`(?i)aws_secret_access_key\s*[:=]\s*['"]?([A-Za-z0-9/+=]{40})`), Description: "AWS Secret Access Key", RuleID: "aws-secret-access-key", MinEntropy: Base64EntropyThreshold, EntropyGroup: 1, Keywords: []string{"aws_secret_access_key"}},
 	// OpenAI
-	{regexp.MustCompile(`sk-[a-zA-Z0-9]{20,}`), "OpenAI API key"},
-	{regexp.MustCompile(`sk-proj-[a-zA-Z0-9]{20,}`), "OpenAI project API key"},
+	{Pattern: regexp.MustCompile(`sk-[a-zA-Z0-9]{20,}`), Description: "OpenAI API key", RuleID: "openai-api-key", Keywords: []string{"sk-"}},
+	{Pattern: regexp.MustCompile(`sk-proj-[a-zA-Z0-9]{20,}`), Description: "OpenAI project API key", RuleID: "openai-project-api-key", Keywords: []string{"sk-proj-"}},
 	// Google
-	{regexp.MustCompile(`AIza[0-9A-Za-z\-_]{35}`), "Google API key"},
+	{Pattern: regexp.MustCompile(`AIza[0-9A-Za-z\-_]{35}`), Description: "Google API key", RuleID: "google-api-key", Keywords: []string{"aiza"}},
 	// GitHub
-	{regexp.MustCompile(`ghp_[a-zA-Z0-9]{36}`), "GitHub Personal Access Token"},
-	{regexp.MustCompile(`gho_[a-zA-Z0-9]{36}`), "GitHub OAuth Token"},
-	{regexp.MustCompile(`ghu_[a-zA-Z0-9]{36}`), "GitHub User Token"},
-	{regexp.MustCompile(`ghs_[a-zA-Z0-9]{36}`), "GitHub Server Token"},
-	{regexp.MustCompile(`ghr_[a-zA-Z0-9]{36}`), "GitHub Refresh Token"},
+	{Pattern: regexp.MustCompile(`ghp_[a-zA-Z0-9]{36}`), Description: "GitHub Personal Access Token", RuleID: "github-pat", Keywords: []string{"ghp_"}},
+	{Pattern: regexp.MustCompile(`gho_[a-zA-Z0-9]{36}`), Description: "GitHub OAuth Token", RuleID: "github-oauth-token", Keywords: []string{"gho_"}},
+	{Pattern: regexp.MustCompile(`ghu_[a-zA-Z0-9]{36}`), Description: "GitHub User Token", RuleID: "github-user-token", Keywords: []string{"ghu_"}},
+	{Pattern: regexp.MustCompile(`ghs_[a-zA-Z0-9]{36}`), Description: "GitHub Server Token", RuleID: "github-server-token", Keywords: []string{"ghs_"}},
+	{Pattern: regexp.MustCompile(`ghr_[a-zA-Z0-9]{36}`), Description: "GitHub Refresh Token", RuleID: "github-refresh-token", Keywords: []string{"ghr_"}},
 	// Bearer tokens
-	{regexp.MustCompile(`(?i)bearer\s+[a-zA-Z0-9_\-\.]{20,}`), "Bearer token"},
-	// Private keys
-	{regexp.MustCompile(`-----BEGIN (RSA |DSA |EC |OPENSSH )?PRIVATE KEY-----`), "Private key"},
+	{Pattern: regexp.MustCompile(`(?i)bearer\s+[a-zA-Z0-9_\-\.]{20,}`), Description: "Bearer token", RuleID: "bearer-token", Keywords: []string{"bearer"}},
+	// Private keys. MultiLine: the header alone isn't enough to tell a real
+	// key from its header mentioned in a comment or doc snippet, so the
+	// scanner also requires a matching footer with a plausible base64 body
+	// in between (see multiLineFindings).
+	{Pattern: regexp.MustCompile(`-----BEGIN (RSA |DSA |EC |OPENSSH )?PRIVATE KEY-----`), Description: "Private key", RuleID: "private-key", MultiLine: true, Terminator: regexp.MustCompile(`-----END (RSA |DSA |EC |OPENSSH )?PRIVATE KEY-----`), Keywords: []string{"-----begin"}},
 	// Slack
-	{regexp.MustCompile(`xox[baprs]-[a-zA-Z0-9\-]{10,}`), "Slack token"},
+	{Pattern: regexp.MustCompile(`xox[baprs]-[a-zA-Z0-9\-]{10,}`), Description: "Slack token", RuleID: "slack-token", Keywords: []string{"xox"}},
 	// Stripe
-	{regexp.MustCompile(`sk_live_[a-zA-Z0-9]{24,}`), "Stripe secret key"},
-	{regexp.MustCompile(`rk_live_[a-zA-Z0-9]{24,}`), "Stripe restricted key"},
+	{Pattern: regexp.MustCompile(`sk_live_[a-zA-Z0-9]{24,}`), Description: "Stripe secret key", RuleID: "stripe-secret-key", Keywords: []string{"sk_live_"}},
+	{Pattern: regexp.MustCompile(`rk_live_[a-zA-Z0-9]{24,}`), Description: "Stripe restricted key", RuleID: "stripe-restricted-key", Keywords: []string{"rk_live_"}},
 	// SendGrid
-	{regexp.MustCompile(`SG\.[a-zA-Z0-9_\-]{20,}\.[a-zA-Z0-9_\-]{20,}`), "SendGrid API key"},
+	{Pattern: regexp.MustCompile(`SG\.[a-zA-Z0-9_\-]{20,}\.[a-zA-Z0-9_\-]{20,}`), Description: "SendGrid API key", RuleID: "sendgrid-api-key", Keywords: []string{"sg."}},
 	// Database connection strings
-	{regexp.MustCompile(`mongodb(\+srv)?://[^:]+:[^@\s]+@`), "MongoDB connection string"},
-	{regexp.MustCompile(`postgres(ql)?://[^:]+:[^@\s]+@`), "PostgreSQL connection string"},
-	{regexp.MustCompile(`mysql://[^:]+:[^@\s]+@`), "MySQL connection string"},
+	{Pattern: regexp.MustCompile(`mongodb(\+srv)?://[^:]+:[^@\s]+@`), Description: "MongoDB connection string", RuleID: "mongodb-connection-string", Keywords: []string{"mongodb"}},
+	{Pattern: regexp.MustCompile(`postgres(ql)?://[^:]+:[^@\s]+@`), Description: "PostgreSQL connection string", RuleID: "postgresql-connection-string", Keywords: []string{"postgres"}},
+	{Pattern: regexp.MustCompile(`mysql://[^:]+:[^@\s]+@`), Description: "MySQL connection string", RuleID: "mysql-connection-string", Keywords: []string{"mysql://"}},
 	// Anthropic
-	{regexp.MustCompile(`sk-ant-[a-zA-Z0-9\-]{20,}`), "Anthropic API key"},
-	// Discord
-	{regexp.MustCompile(`[MN][A-Za-z\d]{23,}\.[A-Za-z\d_-]{6}\.[A-Za-z\d_-]{27}`), "Discord bot token"},
+	{Pattern: regexp.MustCompile(`sk-ant-[a-zA-Z0-9\-]{20,}`), Description: "Anthropic API key", RuleID: "anthropic-api-key", Keywords: []string{"sk-ant-"}},
+	// Discord. No short literal anchor exists for this shape, so it has no
+	// Keywords and is always evaluated (see prefilter.go's no-anchor bucket).
+	{Pattern: regexp.MustCompile(`[MN][A-Za-z\d]{23,}\.[A-Za-z\d_-]{6}\.[A-Za-z\d_-]{27}`), Description: "Discord bot token", RuleID: "discord-bot-token"},
 	// npm
-	{regexp.MustCompile(`npm_[a-zA-Z0-9]{36}`), "npm access token"},
+	{Pattern: regexp.MustCompile(`npm_[a-zA-Z0-9]{36}`), Description: "npm access token", RuleID: "npm-access-token", Keywords: []string{"npm_"}},
 	// PyPI
-	{regexp.MustCompile(`pypi-[a-zA-Z0-9]{43,}`), "PyPI API token"},
-	// Twilio
-	{regexp.MustCompile(`SK[a-fA-F0-9]{32}`), "Twilio API key"},
+	{Pattern: regexp.MustCompile(`pypi-[a-zA-Z0-9]{43,}`), Description: "PyPI API token", RuleID: "pypi-api-token", Keywords: []string{"pypi-"}},
+	// Twilio. "SK" is too short/common a literal to usefully anchor, so this
+	// also has no Keywords.
+	{Pattern: regexp.MustCompile(`SK[a-fA-F0-9]{32}`), Description: "Twilio API key", RuleID: "twilio-api-key"},
 	// Mailgun
-	{regexp.MustCompile(`key-[a-zA-Z0-9]{32}`), "Mailgun API key"},
+	{Pattern: regexp.MustCompile(`key-[a-zA-Z0-9]{32}`), Description: "Mailgun API key", RuleID: "mailgun-api-key", Keywords: []string{"key-"}},
 }
+
+// activeSecretPatterns is the pattern set actually used by
+// checkContentForSecrets: secretPatterns plus any custom rules a repo
+// declared in .security-hooks.json. Set once per run by ApplyRepoConfig
+// before scanning begins, mirroring defaultSkipMatcher/defaultPathPolicy's
+// pattern of a package-level override rather than threading a config value
+// through every scan call.
+var activeSecretPatterns = secretPatterns
+
+// activePrefilter is the Aho-Corasick prefilter built from
+// activeSecretPatterns' Keywords (see prefilter.go), kept in lockstep with
+// it: ApplyRepoConfig rebuilds both together whenever a repo's custom rules
+// change the active pattern set.
+var activePrefilter = buildPrefilter(activeSecretPatterns)
@@ -7,7 +7,7 @@ import (
 
 func Test_SecretPatterns_Count(t *testing.T) {
 	got := len(secretPatterns)
-	want := 26
+	want := 27
 	if got != want {
 		t.Errorf("len(secretPatterns) = %d, want %d", got, want)
 	}
@@ -65,6 +65,21 @@ func Test_SecretPatterns_NonNilPatterns(t *testing.T) {
 	}
 }
 
+func Test_SecretPatterns_UniqueNonEmptyRuleIDs(t *testing.T) {
+	seen := make(map[string]bool, len(secretPatterns))
+	for i, sp := range secretPatterns {
+		t.Run(sp.Description, func(t *testing.T) {
+			if sp.RuleID == "" {
+				t.Errorf("secretPatterns[%d] (%q) has empty RuleID", i, sp.Description)
+			}
+			if seen[sp.RuleID] {
+				t.Errorf("secretPatterns[%d] (%q) reuses RuleID %q", i, sp.Description, sp.RuleID)
+			}
+			seen[sp.RuleID] = true
+		})
+	}
+}
+
 func Test_Constants(t *testing.T) {
 	tests := []struct {
 		name string
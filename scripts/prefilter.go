@@ -0,0 +1,166 @@
+package main
+
+import "strings"
+
+// prefilter.go implements a small Aho-Corasick automaton so
+// checkContentForSecrets doesn't have to run every one of activeSecretPatterns'
+// regexes against content it can't possibly match. Most patterns require a
+// short, cheap ASCII anchor somewhere in their match (SecretPattern.Keywords,
+// e.g. "ghp_", "sk-ant-", "-----begin"); a single automaton pass over content
+// finds which anchors are present, and only patterns whose anchor turned up -
+// plus any pattern with no anchor at all - are worth evaluating. This is the
+// same prefilter trick gitleaks uses ahead of its regex set.
+
+// acNode is one state in the Aho-Corasick trie.
+type acNode struct {
+	children map[byte]*acNode
+	fail     *acNode
+	output   []int // indexes into the keyword list that end at this node
+}
+
+// ahoCorasick is a trie of keywords with failure links, ready to find every
+// keyword occurring anywhere in a text with a single pass.
+type ahoCorasick struct {
+	root *acNode
+}
+
+// buildAhoCorasick compiles keywords (already lowercased) into an
+// ahoCorasick automaton. An empty keywords yields an automaton that matches
+// nothing.
+func buildAhoCorasick(keywords []string) *ahoCorasick {
+	root := &acNode{children: make(map[byte]*acNode)}
+
+	for i, kw := range keywords {
+		node := root
+		for j := 0; j < len(kw); j++ {
+			c := kw[j]
+			child, ok := node.children[c]
+			if !ok {
+				child = &acNode{children: make(map[byte]*acNode)}
+				node.children[c] = child
+			}
+			node = child
+		}
+		node.output = append(node.output, i)
+	}
+
+	// Breadth-first over the trie to wire up failure links: a node's fail
+	// link is the longest proper suffix of its path that's also a path from
+	// root, mirroring the standard Aho-Corasick construction.
+	queue := make([]*acNode, 0, len(keywords))
+	for _, child := range root.children {
+		child.fail = root
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for c, child := range node.children {
+			queue = append(queue, child)
+
+			f := node.fail
+			for f != nil {
+				if fc, ok := f.children[c]; ok {
+					child.fail = fc
+					break
+				}
+				f = f.fail
+			}
+			if child.fail == nil {
+				child.fail = root
+			}
+			// node.fail was enqueued (and therefore fully wired) before
+			// node itself, so child.fail.output is already complete here.
+			child.output = append(child.output, child.fail.output...)
+		}
+	}
+
+	return &ahoCorasick{root: root}
+}
+
+// find returns the set of keyword indexes (as built by buildAhoCorasick)
+// occurring anywhere in text, which must already be lowercased to match how
+// patternPrefilter.candidates lowercases content before searching.
+func (ac *ahoCorasick) find(text string) map[int]struct{} {
+	found := make(map[int]struct{})
+	node := ac.root
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		for node != ac.root {
+			if _, ok := node.children[c]; ok {
+				break
+			}
+			node = node.fail
+		}
+		if child, ok := node.children[c]; ok {
+			node = child
+		}
+		for _, kwIdx := range node.output {
+			found[kwIdx] = struct{}{}
+		}
+	}
+
+	return found
+}
+
+// patternPrefilter maps a []SecretPattern to the automaton and index
+// bookkeeping needed to answer "which of these patterns are even worth
+// trying against this content", built once by buildPrefilter and reused for
+// every file scanned against that pattern set.
+type patternPrefilter struct {
+	automaton       *ahoCorasick
+	keywordPatterns [][]int // keyword index -> pattern indexes requiring it
+	noAnchor        []int   // pattern indexes with no Keywords, always candidates
+}
+
+// buildPrefilter compiles patterns' Keywords into a patternPrefilter. Call
+// it once per pattern set (see activePrefilter and ApplyRepoConfig), not per
+// file - the automaton build cost is amortized across every scan.
+func buildPrefilter(patterns []SecretPattern) *patternPrefilter {
+	pf := &patternPrefilter{}
+
+	var keywords []string
+	keywordIndex := make(map[string]int)
+
+	for patIdx, p := range patterns {
+		if len(p.Keywords) == 0 {
+			pf.noAnchor = append(pf.noAnchor, patIdx)
+			continue
+		}
+		for _, kw := range p.Keywords {
+			kw = strings.ToLower(kw)
+			kwIdx, ok := keywordIndex[kw]
+			if !ok {
+				kwIdx = len(keywords)
+				keywordIndex[kw] = kwIdx
+				keywords = append(keywords, kw)
+				pf.keywordPatterns = append(pf.keywordPatterns, nil)
+			}
+			pf.keywordPatterns[kwIdx] = append(pf.keywordPatterns[kwIdx], patIdx)
+		}
+	}
+
+	pf.automaton = buildAhoCorasick(keywords)
+	return pf
+}
+
+// candidates returns the indexes (into the []SecretPattern pf was built
+// from) worth evaluating against content: every no-anchor pattern, plus any
+// pattern whose Keywords anchor was found. It's safe to call concurrently.
+func (pf *patternPrefilter) candidates(content string) map[int]struct{} {
+	result := make(map[int]struct{}, len(pf.noAnchor))
+	for _, patIdx := range pf.noAnchor {
+		result[patIdx] = struct{}{}
+	}
+
+	lower := strings.ToLower(content)
+	for kwIdx := range pf.automaton.find(lower) {
+		for _, patIdx := range pf.keywordPatterns[kwIdx] {
+			result[patIdx] = struct{}{}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,81 @@
+package main
+
+import "testing"
+
+func Test_AhoCorasick_Find(t *testing.T) {
+	ac := buildAhoCorasick([]string{"ghp_", "sk-ant-", "-----begin"})
+
+	cases := []struct {
+		name string
+		text string
+		want []int
+	}{
+		{"no keywords present", "just some ordinary source code", nil},
+		{"single keyword", "token := \"ghp_abc123\"", []int{0}},
+		{"multiple keywords", "sk-ant-xyz and -----begin rsa private key-----", []int{1, 2}},
+		{"keyword at start and end overlap safe", "ghp_ghp_", []int{0}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ac.find(tc.text)
+			for _, want := range tc.want {
+				if _, ok := got[want]; !ok {
+					t.Errorf("find(%q) = %v, missing expected index %d", tc.text, got, want)
+				}
+			}
+			if len(tc.want) == 0 && len(got) != 0 {
+				t.Errorf("find(%q) = %v, want empty", tc.text, got)
+			}
+		})
+	}
+}
+
+func Test_BuildPrefilter_Candidates(t *testing.T) {
+	patterns := []SecretPattern{
+		{RuleID: "a", Keywords: []string{"ghp_"}},
+		{RuleID: "b", Keywords: []string{"sk-ant-"}},
+		{RuleID: "c"}, // no anchor: always a candidate
+	}
+	pf := buildPrefilter(patterns)
+
+	t.Run("no anchors present still includes no-anchor pattern", func(t *testing.T) {
+		got := pf.candidates("nothing interesting here")
+		if _, ok := got[2]; !ok {
+			t.Errorf("candidates = %v, want index 2 (no-anchor pattern) present", got)
+		}
+		if _, ok := got[0]; ok {
+			t.Errorf("candidates = %v, did not want index 0", got)
+		}
+		if _, ok := got[1]; ok {
+			t.Errorf("candidates = %v, did not want index 1", got)
+		}
+	})
+
+	t.Run("keyword present selects its pattern", func(t *testing.T) {
+		got := pf.candidates("leaked: ghp_0123456789")
+		if _, ok := got[0]; !ok {
+			t.Errorf("candidates = %v, want index 0 present", got)
+		}
+		if _, ok := got[1]; ok {
+			t.Errorf("candidates = %v, did not want index 1", got)
+		}
+	})
+
+	t.Run("keyword match is case-insensitive", func(t *testing.T) {
+		got := pf.candidates("GHP_0123456789")
+		if _, ok := got[0]; !ok {
+			t.Errorf("candidates = %v, want index 0 present for uppercase match", got)
+		}
+	})
+}
+
+func Test_BuildPrefilter_NoKeywordsAtAll(t *testing.T) {
+	patterns := []SecretPattern{{RuleID: "a"}, {RuleID: "b"}}
+	pf := buildPrefilter(patterns)
+
+	got := pf.candidates("anything at all")
+	if len(got) != 2 {
+		t.Errorf("candidates = %v, want both no-anchor patterns present", got)
+	}
+}
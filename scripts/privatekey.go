@@ -0,0 +1,89 @@
+package main
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// base64BodyRegex matches a PEM block's body once line breaks and whitespace
+// are stripped: base64 alphabet plus optional '=' padding.
+var base64BodyRegex = regexp.MustCompile(`^[A-Za-z0-9+/]+=*$`)
+
+// minPEMBodyLength is the minimum stripped-base64 length a PEM block's
+// interior must reach before it's treated as a plausible private key rather
+// than the literal header string appearing in a comment or doc snippet.
+const minPEMBodyLength = 100
+
+// multiLineFindings reports pattern's header matches in content, but only
+// those followed later by pattern.Terminator with a body in between that
+// looks like a real PEM-encoded key: valid base64 of plausible length. A
+// header with no matching footer at all (truncated, or just the string
+// mentioned in prose) is not reported. When the body also parses as a known
+// private-key format via pem.Decode/x509, the key type is appended to the
+// finding's message.
+func multiLineFindings(pattern SecretPattern, filePath, content string, lineOffset int) []Finding {
+	var findings []Finding
+
+	for _, header := range pattern.Pattern.FindAllStringIndex(content, -1) {
+		headerStart, headerEnd := header[0], header[1]
+
+		term := pattern.Terminator.FindStringIndex(content[headerEnd:])
+		if term == nil {
+			continue
+		}
+		termStart, termEnd := headerEnd+term[0], headerEnd+term[1]
+
+		body := stripPEMWhitespace(content[headerEnd:termStart])
+		if len(body) < minPEMBodyLength || !base64BodyRegex.MatchString(body) {
+			continue
+		}
+
+		message := fmt.Sprintf("Found potential %s", pattern.Description)
+		if keyType := confirmPEMKeyType(content[headerStart:termEnd]); keyType != "" {
+			message = fmt.Sprintf("%s (%s, confirmed)", message, keyType)
+		}
+
+		findings = append(findings, newFinding(pattern.RuleID, filePath, content, headerStart, termEnd, lineOffset, message))
+	}
+
+	return findings
+}
+
+// stripPEMWhitespace removes the newlines, carriage returns, and spaces a
+// PEM body is conventionally wrapped with, leaving just the base64 payload.
+func stripPEMWhitespace(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '\n', '\r', ' ', '\t':
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// confirmPEMKeyType attempts to decode block as a PEM-encoded private key
+// and identify its concrete format. Returns "" if block isn't valid PEM or
+// doesn't parse as any of the key types this hook confirms (e.g. OpenSSH's
+// own format, or an encrypted key), in which case the finding is still
+// reported by multiLineFindings, just without the "(type, confirmed)"
+// suffix.
+func confirmPEMKeyType(block string) string {
+	decoded, _ := pem.Decode([]byte(block))
+	if decoded == nil {
+		return ""
+	}
+
+	if _, err := x509.ParsePKCS1PrivateKey(decoded.Bytes); err == nil {
+		return "RSA"
+	}
+	if _, err := x509.ParsePKCS8PrivateKey(decoded.Bytes); err == nil {
+		return "PKCS8"
+	}
+	if _, err := x509.ParseECPrivateKey(decoded.Bytes); err == nil {
+		return "EC"
+	}
+	return ""
+}
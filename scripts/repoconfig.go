@@ -0,0 +1,317 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// repoConfigFileName is the per-repo customization file, discovered by
+// RepoConfigPath via --config or by walking up from CLAUDE_PROJECT_DIR,
+// alongside .secrets-baseline.json. JSON rather than TOML/YAML: it's the
+// format the rest of this tool already uses for structured config, and the
+// only one parseable without adding a dependency.
+const repoConfigFileName = ".security-hooks.json"
+
+// CustomRuleConfig is one user-defined regex rule from .security-hooks.json,
+// merged into the built-in secretPatterns.
+type CustomRuleConfig struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Regex       string `json:"regex"`
+	// Path, if set, is a glob matched against a file's base name, scoping
+	// the rule the way an ExternalDetector's fileGlobs does.
+	Path string `json:"path,omitempty"`
+	// MinEntropy and EntropyGroup mirror SecretPattern's fields of the same
+	// name, requiring Regex's EntropyGroup capture to clear MinEntropy
+	// before a match is reported. MinEntropy of 0 (the default) disables
+	// entropy gating for the rule.
+	MinEntropy   float64 `json:"entropy,omitempty"`
+	EntropyGroup int     `json:"entropyGroup,omitempty"`
+	// Keywords, if set, are cheap ASCII anchors Regex's match always
+	// contains; see SecretPattern.Keywords and prefilter.go for how they
+	// let this rule skip its own regex on content that can't match.
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// AllowlistConfig is one user-declared allowlist entry. A finding is
+// suppressed when every field the entry sets matches: Path (a glob against
+// the finding's file), Secret (a SHA-256 hex digest of the exact matched
+// text, see Finding.SecretHash), and Regex (matched against the finding's
+// message, since the matched text itself is redacted before a Finding is
+// built - use Secret to pin an exact known value instead). Commit is parsed
+// for forward compatibility with tooling that audits already-made commits,
+// but this hook runs pre-commit, before a commit SHA exists, so it's never
+// evaluated here.
+// AllowlistConfig's Stopwords are literal substrings (case-insensitive)
+// matched against a finding's message; any match suppresses it, for
+// fixture/test markers that are easier to name outright than to express as
+// Regex.
+// AllowlistConfig's Rules scopes the entry to specific SecretPattern/
+// CustomRuleConfig RuleIDs (e.g. "aws-access-key", "github-pat"), so a repo
+// can allowlist a whole detector by name rather than by path or value.
+type AllowlistConfig struct {
+	Regex     string   `json:"regex,omitempty"`
+	Path      string   `json:"path,omitempty"`
+	Commit    string   `json:"commit,omitempty"`
+	Secret    string   `json:"secret,omitempty"`
+	Stopwords []string `json:"stopwords,omitempty"`
+	Rules     []string `json:"rules,omitempty"`
+}
+
+// RepoConfig is the parsed contents of .security-hooks.json.
+type RepoConfig struct {
+	Rules     []CustomRuleConfig `json:"rules"`
+	Allowlist []AllowlistConfig  `json:"allowlist"`
+	// ScanExtensions lists binary/compound extensions (e.g. ".min.js") that
+	// should be scanned despite binaryExtensions/compoundBinaryExtensions.
+	ScanExtensions []string `json:"scanExtensions"`
+	// DisableBuiltins drops the built-in secretPatterns entirely instead of
+	// merging Rules on top of them, for a repo that wants to run only its
+	// own rule set.
+	DisableBuiltins bool `json:"disableBuiltins,omitempty"`
+	// ExcludedPaths lists additional gitignore-style patterns - the same
+	// syntax as a .securityhooksignore line, including "!" negation - merged
+	// with builtinExcludedPathRules (see skipmatcher.go) so a repo can skip
+	// vendored paths the built-in defaults don't cover, or re-include a
+	// specific subpath the defaults skip. Reach for this for broad,
+	// directory-shaped exclusions (vendor/, node_modules/) that belong with
+	// the rest of the repo's ignore patterns and want "!" negation.
+	ExcludedPaths []string `json:"excludedPaths,omitempty"`
+	// FileFilter adds extension/path/size/content-based pre-scan filtering
+	// on top of ExcludedPaths; see FileFilterConfig. Its BlacklistedPaths
+	// overlaps ExcludedPaths in syntax and effect - reach for it instead of
+	// ExcludedPaths when a single fileFilter block should also carry
+	// extension, size, or content rules for the same files, or when a repo
+	// wants that filtering kept separate from its gitignore-style excludes.
+	FileFilter FileFilterConfig `json:"fileFilter,omitempty"`
+}
+
+// compiledAllowlistEntry is one AllowlistConfig with its regex compiled and
+// secret hash lowercased, ready to test against a Finding.
+type compiledAllowlistEntry struct {
+	regex     *regexp.Regexp
+	path      string
+	secret    string
+	stopwords []string
+	rules     map[string]struct{}
+}
+
+// CompiledRepoConfig is a RepoConfig with its regexes compiled, ready to
+// apply to a scan via ApplyRepoConfig and Allows.
+type CompiledRepoConfig struct {
+	rules           []SecretPattern
+	allowlist       []compiledAllowlistEntry
+	scanExts        map[string]struct{}
+	disableBuiltins bool
+	excludedPaths   []ignoreRule
+	fileFilter      *FileFilter
+}
+
+// RepoConfigPath returns the .security-hooks.json path to use for
+// projectRoot: configFlag verbatim if set (an explicit --config path),
+// otherwise the first .security-hooks.json found walking up from
+// projectRoot to the filesystem root. Falls back to projectRoot itself if
+// none is found anywhere in between, so LoadRepoConfig's "missing file
+// means no customization" path still applies.
+func RepoConfigPath(projectRoot, configFlag string) string {
+	if configFlag != "" {
+		return configFlag
+	}
+
+	for dir := projectRoot; ; {
+		candidate := filepath.Join(dir, repoConfigFileName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return filepath.Join(projectRoot, repoConfigFileName)
+}
+
+// LoadRepoConfig reads and parses path. A missing file is not an error; it
+// yields a nil config, meaning "no per-repo customization".
+func LoadRepoConfig(path string) (*RepoConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %q: %w", path, err)
+	}
+
+	var cfg RepoConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %q: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Compile validates and compiles cfg's regexes. A rule or allowlist entry
+// with an invalid regex is dropped (with a warning to stderr) rather than
+// failing the whole config. A nil cfg compiles to an empty CompiledRepoConfig.
+func (cfg *RepoConfig) Compile() *CompiledRepoConfig {
+	compiled := &CompiledRepoConfig{scanExts: make(map[string]struct{})}
+	if cfg == nil {
+		return compiled
+	}
+
+	for _, r := range cfg.Rules {
+		re, err := regexp.Compile(r.Regex)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping custom rule %q: invalid regex: %v\n", r.ID, err)
+			continue
+		}
+		compiled.rules = append(compiled.rules, SecretPattern{
+			Pattern:      re,
+			Description:  r.Description,
+			RuleID:       r.ID,
+			PathGlob:     r.Path,
+			MinEntropy:   r.MinEntropy,
+			EntropyGroup: r.EntropyGroup,
+			Keywords:     r.Keywords,
+		})
+	}
+
+	for _, a := range cfg.Allowlist {
+		entry := compiledAllowlistEntry{path: a.Path, secret: strings.ToLower(a.Secret)}
+		if a.Regex != "" {
+			re, err := regexp.Compile(a.Regex)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: skipping allowlist entry with invalid regex %q: %v\n", a.Regex, err)
+				continue
+			}
+			entry.regex = re
+		}
+		for _, sw := range a.Stopwords {
+			if sw != "" {
+				entry.stopwords = append(entry.stopwords, strings.ToLower(sw))
+			}
+		}
+		for _, id := range a.Rules {
+			if id != "" {
+				if entry.rules == nil {
+					entry.rules = make(map[string]struct{})
+				}
+				entry.rules[id] = struct{}{}
+			}
+		}
+		if entry.path == "" && entry.secret == "" && entry.regex == nil && len(entry.stopwords) == 0 && len(entry.rules) == 0 {
+			continue
+		}
+		compiled.allowlist = append(compiled.allowlist, entry)
+	}
+
+	for _, ext := range cfg.ScanExtensions {
+		compiled.scanExts[defaultPathPolicy.Normalize(ext)] = struct{}{}
+	}
+
+	compiled.disableBuiltins = cfg.DisableBuiltins
+	compiled.excludedPaths = compilePatternList(cfg.ExcludedPaths, repoConfigFileName)
+	compiled.fileFilter = compileFileFilter(cfg.FileFilter)
+
+	return compiled
+}
+
+// Allows reports whether f matches one of cfg's allowlist entries and
+// should therefore be suppressed. An entry matches only if every field it
+// sets matches f.
+func (cfg *CompiledRepoConfig) Allows(f Finding) bool {
+	if cfg == nil {
+		return false
+	}
+	for _, entry := range cfg.allowlist {
+		if entry.path != "" {
+			if ok, err := filepath.Match(entry.path, f.FilePath); err != nil || !ok {
+				continue
+			}
+		}
+		if entry.secret != "" && entry.secret != f.SecretHash {
+			continue
+		}
+		if entry.regex != nil && !entry.regex.MatchString(f.Message) {
+			continue
+		}
+		if len(entry.stopwords) > 0 && !containsAnyStopword(f.Message, entry.stopwords) {
+			continue
+		}
+		if len(entry.rules) > 0 {
+			if _, listed := entry.rules[f.RuleID]; !listed {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// containsAnyStopword reports whether message contains any of stopwords
+// (already lowercased), case-insensitively.
+func containsAnyStopword(message string, stopwords []string) bool {
+	lower := strings.ToLower(message)
+	for _, sw := range stopwords {
+		if strings.Contains(lower, sw) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterAllowlisted drops any finding cfg.Allows, returning findings
+// unchanged if cfg is nil or declares no allowlist.
+func filterAllowlisted(findings []Finding, cfg *CompiledRepoConfig) []Finding {
+	if cfg == nil || len(cfg.allowlist) == 0 || len(findings) == 0 {
+		return findings
+	}
+
+	surviving := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		if cfg.Allows(f) {
+			continue
+		}
+		surviving = append(surviving, f)
+	}
+	return surviving
+}
+
+// defaultFileFilter is the FileFilter callers of CheckFileForSecrets consult
+// before scanning, installed by ApplyRepoConfig from a repo's
+// .security-hooks.json fileFilter section. nil (the default) never skips.
+var defaultFileFilter *FileFilter
+
+// ApplyRepoConfig installs compiled's custom rules, scan-extension
+// overrides, excludedPaths rules, and fileFilter as the active pattern set /
+// force-scan list / skip list / pre-scan filter for the rest of this run.
+// Call once per process, before scanning begins; it mutates
+// activeSecretPatterns, forceScanExtensions (see patterns.go, files.go),
+// defaultSkipMatcher, and defaultFileFilter rather than threading a config
+// value through every scan call, following the same pattern as
+// defaultPathPolicy.
+func ApplyRepoConfig(compiled *CompiledRepoConfig) {
+	if compiled == nil {
+		return
+	}
+	if compiled.disableBuiltins {
+		activeSecretPatterns = compiled.rules
+		activePrefilter = buildPrefilter(activeSecretPatterns)
+	} else if len(compiled.rules) > 0 {
+		merged := make([]SecretPattern, 0, len(secretPatterns)+len(compiled.rules))
+		merged = append(merged, secretPatterns...)
+		merged = append(merged, compiled.rules...)
+		activeSecretPatterns = merged
+		activePrefilter = buildPrefilter(activeSecretPatterns)
+	}
+	for ext := range compiled.scanExts {
+		forceScanExtensions[ext] = struct{}{}
+	}
+	defaultSkipMatcher.SetExcludedPaths(compiled.excludedPaths)
+	defaultFileFilter = compiled.fileFilter
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_LoadRepoConfig_MissingFileIsNil(t *testing.T) {
+	cfg, err := LoadRepoConfig(filepath.Join(t.TempDir(), ".security-hooks.json"))
+	if err != nil {
+		t.Fatalf("LoadRepoConfig() error = %v, want nil", err)
+	}
+	if cfg != nil {
+		t.Errorf("LoadRepoConfig() on missing file = %+v, want nil", cfg)
+	}
+}
+
+func Test_LoadRepoConfig_InvalidJSONErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".security-hooks.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := LoadRepoConfig(path); err == nil {
+		t.Error("LoadRepoConfig() error = nil, want an error for malformed JSON")
+	}
+}
+
+func Test_Compile_NilConfigYieldsEmptyNoOp(t *testing.T) {
+	var cfg *RepoConfig
+	compiled := cfg.Compile()
+
+	if len(compiled.rules) != 0 || len(compiled.allowlist) != 0 || len(compiled.scanExts) != 0 {
+		t.Errorf("Compile() on nil config = %+v, want all empty", compiled)
+	}
+	if compiled.Allows(Finding{FilePath: "a.py"}) {
+		t.Error("Allows() on a nil-derived config should never suppress anything")
+	}
+}
+
+func Test_Compile_SkipsInvalidRegexes(t *testing.T) {
+	cfg := &RepoConfig{
+		Rules: []CustomRuleConfig{
+			{ID: "good-rule", Regex: `foo-[0-9]+`},
+			{ID: "bad-rule", Regex: `foo-[0-9+`}, // unterminated class
+		},
+		Allowlist: []AllowlistConfig{
+			{Regex: `known-fixture`},
+			{Regex: `[unterminated`},
+		},
+	}
+
+	compiled := cfg.Compile()
+	if len(compiled.rules) != 1 || compiled.rules[0].RuleID != "good-rule" {
+		t.Errorf("Compile() rules = %+v, want only good-rule", compiled.rules)
+	}
+	if len(compiled.allowlist) != 1 {
+		t.Errorf("Compile() allowlist = %+v, want 1 entry with the valid regex", compiled.allowlist)
+	}
+}
+
+func Test_Allows_MatchesOnAllSetFields(t *testing.T) {
+	cfg := &RepoConfig{
+		Allowlist: []AllowlistConfig{
+			{Path: "testdata/*.py", Secret: "deadbeef"},
+		},
+	}
+	compiled := cfg.Compile()
+
+	allowed := Finding{FilePath: "testdata/fixture.py", SecretHash: "deadbeef"}
+	if !compiled.Allows(allowed) {
+		t.Error("Allows() = false, want true when both path and secret match")
+	}
+
+	wrongSecret := Finding{FilePath: "testdata/fixture.py", SecretHash: "other"}
+	if compiled.Allows(wrongSecret) {
+		t.Error("Allows() = true, want false when secret differs even though path matches")
+	}
+
+	wrongPath := Finding{FilePath: "src/fixture.py", SecretHash: "deadbeef"}
+	if compiled.Allows(wrongPath) {
+		t.Error("Allows() = true, want false when path doesn't match the glob")
+	}
+}
+
+func Test_Allows_RuleIDScopedEntry(t *testing.T) {
+	cfg := &RepoConfig{
+		Allowlist: []AllowlistConfig{
+			{Rules: []string{"aws-access-key", "github-pat"}},
+		},
+	}
+	compiled := cfg.Compile()
+
+	if !compiled.Allows(Finding{FilePath: "a.py", RuleID: "github-pat"}) {
+		t.Error("Allows() = false, want true for a RuleID listed in Rules")
+	}
+	if compiled.Allows(Finding{FilePath: "a.py", RuleID: "anthropic-api-key"}) {
+		t.Error("Allows() = true, want false for a RuleID not listed in Rules")
+	}
+}
+
+func Test_filterAllowlisted_DropsOnlyAllowedFindings(t *testing.T) {
+	cfg := &RepoConfig{Allowlist: []AllowlistConfig{{Secret: "deadbeef"}}}
+	compiled := cfg.Compile()
+
+	findings := []Finding{
+		{FilePath: "a.py", SecretHash: "deadbeef"},
+		{FilePath: "b.py", SecretHash: "other"},
+	}
+
+	surviving := filterAllowlisted(findings, compiled)
+	if len(surviving) != 1 || surviving[0].FilePath != "b.py" {
+		t.Errorf("filterAllowlisted() = %+v, want only b.py to survive", surviving)
+	}
+}
+
+func Test_RepoConfigPath_ExplicitFlagWins(t *testing.T) {
+	got := RepoConfigPath(t.TempDir(), "/explicit/path.json")
+	if got != "/explicit/path.json" {
+		t.Errorf("RepoConfigPath() = %q, want the explicit --config path verbatim", got)
+	}
+}
+
+func Test_RepoConfigPath_WalksUpFromProjectRoot(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b", "c")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	want := filepath.Join(root, repoConfigFileName)
+	if err := os.WriteFile(want, []byte("{}"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if got := RepoConfigPath(nested, ""); got != want {
+		t.Errorf("RepoConfigPath() = %q, want %q found by walking up", got, want)
+	}
+}
+
+func Test_RepoConfigPath_FallsBackToProjectRootWhenNoneFound(t *testing.T) {
+	root := t.TempDir()
+	want := filepath.Join(root, repoConfigFileName)
+
+	if got := RepoConfigPath(root, ""); got != want {
+		t.Errorf("RepoConfigPath() = %q, want %q as the no-config-found fallback", got, want)
+	}
+}
+
+func Test_Compile_DisableBuiltinsAndStopwords(t *testing.T) {
+	cfg := &RepoConfig{
+		DisableBuiltins: true,
+		Allowlist: []AllowlistConfig{
+			{Stopwords: []string{"TEST-FIXTURE"}},
+		},
+	}
+	compiled := cfg.Compile()
+
+	if !compiled.disableBuiltins {
+		t.Error("Compile() disableBuiltins = false, want true")
+	}
+	if !compiled.Allows(Finding{Message: "found a test-fixture secret"}) {
+		t.Error("Allows() = false, want true: stopword match should be case-insensitive")
+	}
+	if compiled.Allows(Finding{Message: "found a real secret"}) {
+		t.Error("Allows() = true, want false when no stopword is present in the message")
+	}
+}
+
+func Test_ApplyRepoConfig_DisableBuiltinsDropsBuiltinPatterns(t *testing.T) {
+	origPatterns := activeSecretPatterns
+	t.Cleanup(func() { activeSecretPatterns = origPatterns })
+
+	cfg := &RepoConfig{
+		DisableBuiltins: true,
+		Rules:           []CustomRuleConfig{{ID: "only-rule", Regex: `only-[0-9]+`}},
+	}
+	ApplyRepoConfig(cfg.Compile())
+
+	if len(activeSecretPatterns) != 1 || activeSecretPatterns[0].RuleID != "only-rule" {
+		t.Errorf("ApplyRepoConfig() activeSecretPatterns = %+v, want only the custom rule", activeSecretPatterns)
+	}
+}
+
+func Test_Compile_PassesThroughCustomRuleEntropyFields(t *testing.T) {
+	cfg := &RepoConfig{
+		Rules: []CustomRuleConfig{
+			{ID: "custom-entropy-rule", Regex: `token=([a-z0-9]+)`, MinEntropy: 4.0, EntropyGroup: 1},
+		},
+	}
+	compiled := cfg.Compile()
+
+	if len(compiled.rules) != 1 {
+		t.Fatalf("Compile() rules = %+v, want 1 entry", compiled.rules)
+	}
+	got := compiled.rules[0]
+	if got.MinEntropy != 4.0 || got.EntropyGroup != 1 {
+		t.Errorf("Compile() rule MinEntropy/EntropyGroup = %v/%d, want 4.0/1", got.MinEntropy, got.EntropyGroup)
+	}
+}
+
+func Test_Compile_ExcludedPaths(t *testing.T) {
+	cfg := &RepoConfig{ExcludedPaths: []string{"generated/", "!generated/keep.go"}}
+	compiled := cfg.Compile()
+
+	if len(compiled.excludedPaths) != 2 {
+		t.Fatalf("Compile() excludedPaths = %+v, want 2 entries", compiled.excludedPaths)
+	}
+}
+
+func Test_ApplyRepoConfig_InstallsExcludedPaths(t *testing.T) {
+	origMatcher := defaultSkipMatcher
+	t.Cleanup(func() { defaultSkipMatcher = origMatcher })
+	defaultSkipMatcher = NewSkipMatcher()
+
+	root := t.TempDir()
+	cfg := &RepoConfig{ExcludedPaths: []string{"generated/"}}
+	ApplyRepoConfig(cfg.Compile())
+
+	path := filepath.Join(root, "generated", "schema.go")
+	if got, reason := defaultSkipMatcher.Match(path); !got {
+		t.Errorf("Match(%q) = false (%q), want true after ApplyRepoConfig installs excludedPaths", path, reason)
+	}
+}
+
+func Test_ApplyRepoConfig_MergesRulesAndScanExtensions(t *testing.T) {
+	origPatterns := activeSecretPatterns
+	origForceScan := forceScanExtensions
+	t.Cleanup(func() {
+		activeSecretPatterns = origPatterns
+		forceScanExtensions = origForceScan
+	})
+	forceScanExtensions = map[string]struct{}{}
+
+	cfg := &RepoConfig{
+		Rules:          []CustomRuleConfig{{ID: "custom-rule", Regex: `custom-[0-9]+`}},
+		ScanExtensions: []string{".min.js"},
+	}
+	ApplyRepoConfig(cfg.Compile())
+
+	if len(activeSecretPatterns) != len(secretPatterns)+1 {
+		t.Errorf("ApplyRepoConfig() activeSecretPatterns has %d entries, want %d", len(activeSecretPatterns), len(secretPatterns)+1)
+	}
+	if !isForceScanExtension("app.min.js") {
+		t.Error("ApplyRepoConfig() did not register the custom scanExtensions entry")
+	}
+}
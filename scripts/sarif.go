@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// sarifSchemaURI and sarifVersion identify the SARIF 2.1.0 schema used by
+// BuildSarifReport's output.
+const (
+	sarifSchemaURI = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+	sarifVersion   = "2.1.0"
+	sarifToolName  = "claude-secret-scan-hook"
+	// sarifDefaultLevel is every rule's defaultConfiguration.level: a
+	// detected secret is always worth blocking on, so there's no "warning"
+	// tier to distinguish.
+	sarifDefaultLevel = "error"
+	// sarifHelpURIBase, plus a rule ID, is every rule's helpUri: an anchor
+	// into this tool's own rule catalog rather than an external page, since
+	// the rules themselves are this repo's curated patterns.
+	sarifHelpURIBase = "https://github.com/JamesPrial/security-hooks#"
+)
+
+// sarifRuleDescriptions gives a human-readable shortDescription for the two
+// detectors that aren't backed by a SecretPattern (which already carries its
+// own Description).
+var sarifRuleDescriptions = map[string]string{
+	HardcodedEnvValueRuleID: "Hardcoded value matching a secret from the repo's .env file",
+	HighEntropyStringRuleID: "High-entropy string not matched by any curated pattern",
+}
+
+// sarifLog is the top-level SARIF log document.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string                    `json:"id"`
+	Name                 string                    `json:"name"`
+	ShortDescription     sarifMessage              `json:"shortDescription,omitempty"`
+	HelpURI              string                    `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifDefaultConfiguration `json:"defaultConfiguration,omitempty"`
+}
+
+type sarifDefaultConfiguration struct {
+	Level string `json:"level,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int          `json:"startLine"`
+	StartColumn int          `json:"startColumn,omitempty"`
+	Snippet     sarifMessage `json:"snippet,omitempty"`
+}
+
+// sarifRuleIDs enumerates every detector's rule id, in the order the driver
+// should list them: activeSecretPatterns first (built-in rules plus any
+// repo-config custom rules merged in by ApplyRepoConfig), then the two
+// detectors that aren't backed by a SecretPattern.
+func sarifRuleIDs() []string {
+	ids := make([]string, 0, len(activeSecretPatterns)+2)
+	seen := make(map[string]struct{}, len(activeSecretPatterns)+2)
+	for _, pattern := range activeSecretPatterns {
+		if _, ok := seen[pattern.RuleID]; ok {
+			continue
+		}
+		seen[pattern.RuleID] = struct{}{}
+		ids = append(ids, pattern.RuleID)
+	}
+	ids = append(ids, HardcodedEnvValueRuleID, HighEntropyStringRuleID)
+	return ids
+}
+
+// ruleDescription returns id's human-readable shortDescription: a
+// SecretPattern's own Description for a pattern-backed rule, or the static
+// text in sarifRuleDescriptions for the two detectors that aren't.
+func ruleDescription(id string) string {
+	for _, pattern := range activeSecretPatterns {
+		if pattern.RuleID == id {
+			return pattern.Description
+		}
+	}
+	return sarifRuleDescriptions[id]
+}
+
+// BuildSarifReport converts findings into a SARIF 2.1.0 log with a
+// driver.rules entry for every detector, even ones that produced no
+// findings in this scan.
+func BuildSarifReport(findings []Finding) sarifLog {
+	rules := make([]sarifRule, 0, len(activeSecretPatterns)+2)
+	for _, id := range sarifRuleIDs() {
+		rules = append(rules, sarifRule{
+			ID:                   id,
+			Name:                 id,
+			ShortDescription:     sarifMessage{Text: ruleDescription(id)},
+			HelpURI:              sarifHelpURIBase + id,
+			DefaultConfiguration: sarifDefaultConfiguration{Level: sarifDefaultLevel},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		results = append(results, sarifResult{
+			RuleID: f.RuleID,
+			Level:  sarifDefaultLevel,
+			Message: sarifMessage{
+				Text: f.Message,
+			},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: f.FilePath},
+						Region: sarifRegion{
+							StartLine:   f.Line,
+							StartColumn: f.Column,
+							Snippet:     sarifMessage{Text: f.Snippet},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:  sarifToolName,
+						Rules: rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+}
+
+// WriteSarifReport marshals findings as a SARIF 2.1.0 log and writes it to
+// path. Writing the report is best-effort from the caller's point of view:
+// it returns an error instead of exiting, so a failure here can be reported
+// without changing the hook's deny/allow decision.
+func WriteSarifReport(path string, findings []Finding) error {
+	report := BuildSarifReport(findings)
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SARIF report: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write SARIF report to %q: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_BuildSarifReport_ListsEveryDetectorRule(t *testing.T) {
+	report := BuildSarifReport(nil)
+
+	if len(report.Runs) != 1 {
+		t.Fatalf("BuildSarifReport() produced %d runs, want 1", len(report.Runs))
+	}
+
+	rules := report.Runs[0].Tool.Driver.Rules
+	wantCount := len(sarifRuleIDs())
+	if len(rules) != wantCount {
+		t.Errorf("BuildSarifReport() listed %d rules, want %d", len(rules), wantCount)
+	}
+
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		seen[rule.ID] = true
+	}
+	if !seen[HardcodedEnvValueRuleID] || !seen[HighEntropyStringRuleID] {
+		t.Errorf("BuildSarifReport() rules = %v, want it to include %q and %q", rules, HardcodedEnvValueRuleID, HighEntropyStringRuleID)
+	}
+}
+
+func Test_BuildSarifReport_RulesCarryDescriptionAndHelpAndLevel(t *testing.T) {
+	report := BuildSarifReport(nil)
+
+	for _, rule := range report.Runs[0].Tool.Driver.Rules {
+		if rule.ShortDescription.Text == "" {
+			t.Errorf("rule %q has empty shortDescription", rule.ID)
+		}
+		if rule.HelpURI == "" {
+			t.Errorf("rule %q has empty helpUri", rule.ID)
+		}
+		if rule.DefaultConfiguration.Level != "error" {
+			t.Errorf("rule %q defaultConfiguration.level = %q, want %q", rule.ID, rule.DefaultConfiguration.Level, "error")
+		}
+	}
+}
+
+func Test_BuildSarifReport_IncludesCustomRepoConfigRule(t *testing.T) {
+	origPatterns := activeSecretPatterns
+	t.Cleanup(func() { activeSecretPatterns = origPatterns })
+
+	cfg := &RepoConfig{Rules: []CustomRuleConfig{{ID: "custom-rule", Regex: `custom-[0-9]+`, Description: "custom secret"}}}
+	ApplyRepoConfig(cfg.Compile())
+
+	report := BuildSarifReport([]Finding{{RuleID: "custom-rule"}})
+
+	var rule *sarifRule
+	for i, r := range report.Runs[0].Tool.Driver.Rules {
+		if r.ID == "custom-rule" {
+			rule = &report.Runs[0].Tool.Driver.Rules[i]
+		}
+	}
+	if rule == nil {
+		t.Fatalf("BuildSarifReport() driver.rules = %+v, want an entry for the repo-config custom rule", report.Runs[0].Tool.Driver.Rules)
+	}
+	if rule.ShortDescription.Text != "custom secret" {
+		t.Errorf("custom rule shortDescription = %q, want %q", rule.ShortDescription.Text, "custom secret")
+	}
+	if rule.HelpURI == "" {
+		t.Error("custom rule has empty helpUri")
+	}
+	if rule.DefaultConfiguration.Level != sarifDefaultLevel {
+		t.Errorf("custom rule defaultConfiguration.level = %q, want %q", rule.DefaultConfiguration.Level, sarifDefaultLevel)
+	}
+}
+
+func Test_BuildSarifReport_FindingBecomesResult(t *testing.T) {
+	findings := []Finding{
+		{
+			RuleID:   "github-pat",
+			FilePath: "config.py",
+			Line:     3,
+			Column:   9,
+			Message:  "Found potential GitHub Personal Access Token",
+			Snippet:  "gh**********************************en",
+		},
+	}
+
+	report := BuildSarifReport(findings)
+	results := report.Runs[0].Results
+	if len(results) != 1 {
+		t.Fatalf("BuildSarifReport() produced %d results, want 1", len(results))
+	}
+
+	result := results[0]
+	if result.RuleID != "github-pat" {
+		t.Errorf("result.RuleID = %q, want %q", result.RuleID, "github-pat")
+	}
+	if len(result.Locations) != 1 {
+		t.Fatalf("result.Locations has %d entries, want 1", len(result.Locations))
+	}
+
+	loc := result.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "config.py" {
+		t.Errorf("artifactLocation.uri = %q, want %q", loc.ArtifactLocation.URI, "config.py")
+	}
+	if loc.Region.StartLine != 3 || loc.Region.StartColumn != 9 {
+		t.Errorf("region = (line %d, col %d), want (3, 9)", loc.Region.StartLine, loc.Region.StartColumn)
+	}
+}
+
+func Test_WriteSarifReport_WritesValidJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.sarif")
+
+	findings := []Finding{
+		{RuleID: HighEntropyStringRuleID, FilePath: "a.go", Line: 1, Column: 1, Message: "Found potential high-entropy string", Snippet: "re*****ed"},
+	}
+
+	if err := WriteSarifReport(path, findings); err != nil {
+		t.Fatalf("WriteSarifReport() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+
+	var report sarifLog
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("WriteSarifReport() wrote invalid JSON: %v", err)
+	}
+	if report.Version != sarifVersion {
+		t.Errorf("report.Version = %q, want %q", report.Version, sarifVersion)
+	}
+}
+
+func Test_RedactSnippet_Cases(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"short token", "abcd"},
+		{"long token", "sk-ant-" + "abcdefghijklmnopqrstuvwxyz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redacted := redactSnippet(tt.input)
+			if len(redacted) != len(tt.input) {
+				t.Errorf("redactSnippet(%q) has length %d, want %d", tt.input, len(redacted), len(tt.input))
+			}
+			if redacted == tt.input && len(tt.input) > 4 {
+				t.Errorf("redactSnippet(%q) = %q, want it redacted", tt.input, redacted)
+			}
+		})
+	}
+}
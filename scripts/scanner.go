@@ -2,7 +2,7 @@ package main
 
 import (
 	"fmt"
-	"regexp"
+	"path/filepath"
 	"strings"
 )
 
@@ -17,15 +17,30 @@ func GetLineNumber(content string, position int) int {
 	return strings.Count(content[:position], "\n") + 1
 }
 
-// CompileEnvPatterns creates word-boundary-wrapped compiled regexes for each env value.
-// Called once per scan, not per file.
-func CompileEnvPatterns(secretEnvValues map[string]string) map[string]*regexp.Regexp {
-	envPatterns := make(map[string]*regexp.Regexp)
+// GetColumnNumber calculates the 1-based column number for a byte position
+// in content, measured from the start of its line.
+func GetColumnNumber(content string, position int) int {
+	if position > len(content) {
+		position = len(content)
+	}
+	if position < 0 {
+		position = 0
+	}
+	lineStart := strings.LastIndex(content[:position], "\n") + 1
+	return position - lineStart + 1
+}
+
+// CompileEnvPatterns builds a CompiledEnvPattern for each env value, ready
+// to search scanned content for an exact (word-boundary-respecting) match.
+// Called once per scan, not per file. Most .env values are plain literals
+// with no regex metacharacters, so compileEnvPattern picks a literal
+// strings.Index-based matcher for them instead of paying for a compiled
+// regexp.Regexp per value; see envmatch.go.
+func CompileEnvPatterns(secretEnvValues map[string]string) map[string]CompiledEnvPattern {
+	envPatterns := make(map[string]CompiledEnvPattern, len(secretEnvValues))
 
 	for key, value := range secretEnvValues {
-		// Escape special regex characters and wrap with word boundaries
-		pattern := `\b` + regexp.QuoteMeta(value) + `\b`
-		envPatterns[key] = regexp.MustCompile(pattern)
+		envPatterns[key] = compileEnvPattern(value)
 	}
 
 	return envPatterns
@@ -36,31 +51,173 @@ func CompileEnvPatterns(secretEnvValues map[string]string) map[string]*regexp.Re
 //
 //	"filepath:linenum - Found potential description"
 //	"filepath:linenum - Found hardcoded value from .env key 'KEY'"
-func CheckFileForSecrets(filePath, content string, envPatterns map[string]*regexp.Regexp) ([]string, []string) {
-	var patternIssues []string
-	var envIssues []string
+func CheckFileForSecrets(filePath, content string, envPatterns map[string]CompiledEnvPattern) ([]string, []string) {
+	patternIssues, envIssues, _ := CheckFileForSecretsWithFindings(filePath, content, envPatterns)
+	return patternIssues, envIssues
+}
+
+// CheckFileForSecretsWithFindings behaves like CheckFileForSecrets but also
+// returns the structured Findings behind each issue string, for callers that
+// need more than a human-readable line (for example, a SARIF report).
+func CheckFileForSecretsWithFindings(filePath, content string, envPatterns map[string]CompiledEnvPattern) ([]string, []string, []Finding) {
+	return checkContentForSecrets(filePath, content, envPatterns, 0)
+}
+
+// checkContentForSecrets is the shared scanning core behind
+// CheckFileForSecretsWithFindings and ScanAddedHunksWithFindings. lineOffset
+// is added to every line number computed from content, letting callers
+// report line numbers relative to a larger file (for example, a diff hunk's
+// position in the post-image) rather than relative to content itself.
+func checkContentForSecrets(filePath, content string, envPatterns map[string]CompiledEnvPattern, lineOffset int) ([]string, []string, []Finding) {
+	var findings []Finding
 
-	// Check against pre-compiled secret patterns
-	for _, pattern := range secretPatterns {
+	// Check against pre-compiled secret patterns, plus any custom rules a
+	// repo declared in .security-hooks.json (see ApplyRepoConfig). A single
+	// Aho-Corasick pass (activePrefilter) first rules out any pattern whose
+	// Keywords anchor never occurs in content at all, so content with none
+	// of the 26+ built-in anchors skips the regex set entirely.
+	candidates := activePrefilter.candidates(content)
+	for patIdx, pattern := range activeSecretPatterns {
+		if _, ok := candidates[patIdx]; !ok {
+			continue
+		}
+		if pattern.PathGlob != "" {
+			if ok, err := filepath.Match(pattern.PathGlob, filepath.Base(filePath)); err != nil || !ok {
+				continue
+			}
+		}
+		if pattern.MultiLine {
+			findings = append(findings, multiLineFindings(pattern, filePath, content, lineOffset)...)
+			continue
+		}
+		if pattern.MinEntropy > 0 {
+			findings = append(findings, entropyGatedFindings(pattern, filePath, content, lineOffset)...)
+			continue
+		}
 		matches := pattern.Pattern.FindAllStringIndex(content, -1)
 		for _, match := range matches {
-			start := match[0]
-			lineNum := GetLineNumber(content, start)
-			issue := fmt.Sprintf("%s:%d - Found potential %s", filePath, lineNum, pattern.Description)
-			patternIssues = append(patternIssues, issue)
+			start, end := match[0], match[1]
+			findings = append(findings, newFinding(pattern.RuleID, filePath, content, start, end, lineOffset, "Found potential "+pattern.Description))
 		}
 	}
 
 	// Check against environment value patterns
 	for key, pattern := range envPatterns {
-		matches := pattern.FindAllStringIndex(content, -1)
+		matches := pattern.Find(content)
 		for _, match := range matches {
-			start := match[0]
-			lineNum := GetLineNumber(content, start)
-			issue := fmt.Sprintf("%s:%d - Found hardcoded value from .env key '%s'", filePath, lineNum, key)
-			envIssues = append(envIssues, issue)
+			start, end := match[0], match[1]
+			findings = append(findings, newFinding(HardcodedEnvValueRuleID, filePath, content, start, end, lineOffset, fmt.Sprintf("Found hardcoded value from .env key '%s'", key)))
 		}
 	}
 
+	// High-entropy tokens the fixed patterns above miss entirely
+	findings = append(findings, entropyFindings(filePath, content, lineOffset)...)
+
+	// Drop anything explicitly allowed via a security-hooks:allow comment on
+	// the matched line or the one just above it
+	findings = filterAllowDirectives(findings, content, lineOffset)
+
+	patternIssues, envIssues := issuesFromFindings(findings)
+	return patternIssues, envIssues, findings
+}
+
+// issuesFromFindings renders findings into the scanner's plain-string issue
+// lists, splitting hardcoded-.env-value findings into envIssues and
+// everything else into patternIssues. Each issue is tagged with the
+// detector that produced it, so a reader of the deny output can tell a
+// fixed-pattern hit from an entropy-based one at a glance.
+func issuesFromFindings(findings []Finding) (patternIssues, envIssues []string) {
+	for _, f := range findings {
+		issue := formatIssue(f.FilePath, f.Line, fmt.Sprintf("[%s] %s", detectorLabel(f.RuleID), f.Message))
+		if f.RuleID == HardcodedEnvValueRuleID {
+			envIssues = append(envIssues, issue)
+		} else {
+			patternIssues = append(patternIssues, issue)
+		}
+	}
 	return patternIssues, envIssues
 }
+
+// detectorLabel renders a finding's RuleID as the detector name shown in
+// the deny output: "entropy" for the Shannon-entropy scanner, "env" for
+// hardcoded .env values, and "pattern:<ruleID>" for everything matched by a
+// fixed regex (including external detector rule IDs).
+func detectorLabel(ruleID string) string {
+	switch ruleID {
+	case HighEntropyStringRuleID:
+		return "entropy"
+	case HardcodedEnvValueRuleID:
+		return "env"
+	default:
+		return "pattern:" + ruleID
+	}
+}
+
+// entropyGatedFindings reports pattern's matches in content, but only those
+// whose EntropyGroup capture clears pattern.MinEntropy, appending the
+// computed entropy to the finding's message so it's visible in scan output.
+// A match whose EntropyGroup didn't participate (e.g. an optional group)
+// is skipped rather than treated as a non-match.
+func entropyGatedFindings(pattern SecretPattern, filePath, content string, lineOffset int) []Finding {
+	var findings []Finding
+
+	groupIdx := pattern.EntropyGroup * 2
+	for _, match := range pattern.Pattern.FindAllStringSubmatchIndex(content, -1) {
+		if groupIdx+1 >= len(match) || match[groupIdx] < 0 {
+			continue
+		}
+		value := content[match[groupIdx]:match[groupIdx+1]]
+		entropy := shannonEntropy(value)
+		if entropy < pattern.MinEntropy {
+			continue
+		}
+
+		start, end := match[0], match[1]
+		message := fmt.Sprintf("Found potential %s (entropy=%.2f)", pattern.Description, entropy)
+		findings = append(findings, newFinding(pattern.RuleID, filePath, content, start, end, lineOffset, message))
+	}
+
+	return findings
+}
+
+// newFinding builds a Finding for a match spanning [start, end) in content,
+// converting the byte offset into a 1-based line/column pair and redacting
+// the matched text before it's stored.
+func newFinding(ruleID, filePath, content string, start, end, lineOffset int, message string) Finding {
+	lineStart := strings.LastIndex(content[:start], "\n") + 1
+	lineEnd := len(content)
+	if rel := strings.IndexByte(content[end:], '\n'); rel != -1 {
+		lineEnd = end + rel
+	}
+
+	f := Finding{
+		RuleID:     ruleID,
+		FilePath:   filePath,
+		Line:       GetLineNumber(content, start) + lineOffset,
+		Column:     GetColumnNumber(content, start),
+		Message:    message,
+		Snippet:    redactSnippet(content[start:end]),
+		LineHash:   LineHash(content[lineStart:lineEnd], start-lineStart, end-lineStart),
+		SecretHash: SecretHash(content[start:end]),
+	}
+
+	// Live-verify against the credential's own provider so a reviewer can
+	// see whether this is a currently-valid leak rather than a revoked or
+	// fixture value, opt-in only since it calls out over the network with
+	// the matched secret.
+	if VerifyEnabled() {
+		if result, attempted := verifyMatch(ruleID, content[start:end]); attempted {
+			f.VerifyAttempted = true
+			f.Verified = result.Verified
+			f.VerifyMeta = result.Meta
+		}
+	}
+
+	return f
+}
+
+// formatIssue renders an issue string in the scanner's standard
+// "filepath:linenum - description" format.
+func formatIssue(filePath string, lineNum int, description string) string {
+	return fmt.Sprintf("%s:%d - %s", filePath, lineNum, description)
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ignoreFileName is the per-directory file SkipMatcher looks for, named
+// distinctly from .gitignore so it isn't confused with source-control rules.
+const ignoreFileName = ".securityhooksignore"
+
+// ignoreRule is one compiled line from a .securityhooksignore file, a
+// built-in default, or a .security-hooks.json excludedPaths entry.
+type ignoreRule struct {
+	regex   *regexp.Regexp
+	negate  bool
+	dirOnly bool
+	kind    string // "user pattern" or "excluded path", used in ShouldSkip's reason
+	source  string // e.g. ".securityhooksignore:12", used in ShouldSkip's reason
+}
+
+// builtinExcludedPaths are default gitignore-style patterns for content this
+// scanner almost never wants to open: vendored dependencies, package-manager
+// caches, and minified build output. They're checked before any
+// .securityhooksignore rule, same as gitignore's built-in defaults, so a
+// repo can still "!"-negate a specific subpath back in.
+var builtinExcludedPaths = []string{
+	"vendor/",
+	"node_modules/",
+	".git/",
+	"testdata/",
+	"**/*.min.*",
+	"dist/",
+	"build/",
+}
+
+// builtinExcludedPathRules is builtinExcludedPaths, compiled once at
+// package init.
+var builtinExcludedPathRules = compilePatternList(builtinExcludedPaths, "built-in")
+
+// compilePatternList compiles patterns (gitignore-style, same syntax as a
+// .securityhooksignore line, including "!" negation) into ignoreRules,
+// tagged kind for ShouldSkip's reason string.
+func compilePatternList(patterns []string, kind string) []ignoreRule {
+	var rules []ignoreRule
+	for i, pattern := range patterns {
+		if rule := parseIgnoreLine(pattern, i+1, kind); rule != nil {
+			rule.kind = "excluded path"
+			rules = append(rules, *rule)
+		}
+	}
+	return rules
+}
+
+// SkipMatcher loads and caches gitignore-style rules from .securityhooksignore
+// files, searched upward from the file being checked with a repo-root stop,
+// plus any excludedPaths rules installed from a repo's .security-hooks.json
+// via SetExcludedPaths.
+type SkipMatcher struct {
+	mu       sync.Mutex
+	cache    map[string][]ignoreRule
+	excluded []ignoreRule
+}
+
+// NewSkipMatcher returns an empty SkipMatcher ready to load ignore files on demand.
+func NewSkipMatcher() *SkipMatcher {
+	return &SkipMatcher{cache: make(map[string][]ignoreRule)}
+}
+
+// defaultSkipMatcher is the matcher IsEnvFile, IsBinaryFile, and ShouldSkip
+// consult for user-supplied patterns.
+var defaultSkipMatcher = NewSkipMatcher()
+
+// SetExcludedPaths installs extra excluded-path rules - typically a repo's
+// .security-hooks.json excludedPaths, compiled by RepoConfig.Compile - on
+// top of builtinExcludedPathRules. Called once per run by ApplyRepoConfig;
+// a nil or empty rules drops back to the built-in defaults only.
+func (m *SkipMatcher) SetExcludedPaths(rules []ignoreRule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.excluded = rules
+}
+
+// applyRules evaluates rules against relPath in order, updating *matched: a
+// later match always wins over an earlier one (whether from this slice or a
+// previous call), and a negated match clears any previous one, mirroring
+// gitignore's last-match-wins semantics.
+func applyRules(rules []ignoreRule, relPath string, matched **ignoreRule) {
+	for _, rule := range rules {
+		if !rule.regex.MatchString(relPath) {
+			continue
+		}
+		if rule.negate {
+			*matched = nil
+		} else {
+			r := rule
+			*matched = &r
+		}
+	}
+}
+
+// Match reports whether path is matched by any applicable excluded-path
+// default, .security-hooks.json excludedPaths entry, or .securityhooksignore
+// rule. builtinExcludedPathRules and m.excluded are checked first, relative
+// to the repo root, since they're repo-wide defaults; .securityhooksignore
+// files are then walked from the repo root down to path's directory (the
+// first ancestor containing a .git directory) or the filesystem root,
+// whichever comes first. Later matches always win over earlier ones, and a
+// "!"-negated pattern at any stage can re-include a path an earlier stage
+// excluded, mirroring gitignore semantics throughout.
+func (m *SkipMatcher) Match(path string) (bool, string) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false, ""
+	}
+
+	dirs := m.dirsToRoot(filepath.Dir(absPath))
+	rootRelPath, err := filepath.Rel(dirs[0], absPath)
+	if err != nil {
+		return false, ""
+	}
+
+	var matched *ignoreRule
+	applyRules(builtinExcludedPathRules, filepath.ToSlash(rootRelPath), &matched)
+
+	m.mu.Lock()
+	excluded := m.excluded
+	m.mu.Unlock()
+	applyRules(excluded, filepath.ToSlash(rootRelPath), &matched)
+
+	for _, dir := range dirs {
+		relPath, err := filepath.Rel(dir, absPath)
+		if err != nil {
+			continue
+		}
+		applyRules(m.rulesFor(dir), filepath.ToSlash(relPath), &matched)
+	}
+
+	if matched == nil {
+		return false, ""
+	}
+	return true, fmt.Sprintf("%s %s", matched.kind, matched.source)
+}
+
+// dirsToRoot returns the chain of directories from the repo root (or
+// filesystem root) down to startDir, in that order, so callers can apply
+// rules root-most first and let closer directories override.
+func (m *SkipMatcher) dirsToRoot(startDir string) []string {
+	var chain []string
+	dir := startDir
+	for {
+		chain = append(chain, dir)
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// rulesFor returns the parsed rules for dir's .securityhooksignore file,
+// parsing it at most once per directory.
+func (m *SkipMatcher) rulesFor(dir string) []ignoreRule {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if rules, ok := m.cache[dir]; ok {
+		return rules
+	}
+
+	rules := loadIgnoreFile(dir)
+	m.cache[dir] = rules
+	return rules
+}
+
+// loadIgnoreFile parses dir's .securityhooksignore file, if present, into
+// ignoreRules. A missing file yields no rules and no error.
+func loadIgnoreFile(dir string) []ignoreRule {
+	ignorePath := filepath.Join(dir, ignoreFileName)
+
+	f, err := os.Open(ignorePath)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = f.Close() }()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if rule := parseIgnoreLine(scanner.Text(), lineNo, ignoreFileName); rule != nil {
+			rules = append(rules, *rule)
+		}
+	}
+
+	return rules
+}
+
+// parseIgnoreLine compiles a single .securityhooksignore line into an
+// ignoreRule, or returns nil for blank lines and comments.
+func parseIgnoreLine(line string, lineNo int, sourceLabel string) *ignoreRule {
+	pattern := strings.TrimRight(line, " \t")
+	if pattern == "" || strings.HasPrefix(strings.TrimLeft(pattern, " \t"), "#") {
+		return nil
+	}
+
+	negate := strings.HasPrefix(pattern, "!")
+	if negate {
+		pattern = pattern[1:]
+	}
+
+	anchored := strings.HasPrefix(pattern, "/")
+	if anchored {
+		pattern = pattern[1:]
+	}
+
+	dirOnly := strings.HasSuffix(pattern, "/")
+	if dirOnly {
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	if pattern == "" {
+		return nil
+	}
+
+	if !anchored && !strings.Contains(pattern, "/") {
+		// An unanchored, slash-free pattern matches at any depth.
+		pattern = "**/" + pattern
+	}
+
+	return &ignoreRule{
+		regex:   compileIgnorePattern(pattern, dirOnly),
+		negate:  negate,
+		dirOnly: dirOnly,
+		kind:    "user pattern",
+		source:  fmt.Sprintf("%s:%d", sourceLabel, lineNo),
+	}
+}
+
+// compileIgnorePattern translates a gitignore-style glob into an anchored
+// regex matched against a forward-slash relative path. "**" matches any
+// depth (including none) when followed by "/", or anything otherwise; "*"
+// matches within a single path segment; all other characters are literal.
+func compileIgnorePattern(pattern string, dirOnly bool) *regexp.Regexp {
+	runes := []rune(pattern)
+	var sb strings.Builder
+	sb.WriteString("^")
+
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '*':
+			if i+2 < len(runes) && runes[i+2] == '/' {
+				sb.WriteString("(?:.*/)?")
+				i += 2
+			} else {
+				sb.WriteString(".*")
+				i++
+			}
+		case runes[i] == '*':
+			sb.WriteString("[^/]*")
+		case runes[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(runes[i])))
+		}
+	}
+
+	if dirOnly {
+		sb.WriteString("(?:/.*)?")
+	}
+	sb.WriteString("$")
+
+	return regexp.MustCompile(sb.String())
+}
+
+// ShouldSkip is the primary entry point for deciding whether path should be
+// scanned at all. It returns whether to skip and a short human-readable
+// reason ("binary extension", "env file", "excluded path built-in:1",
+// "user pattern .securityhooksignore:12").
+func ShouldSkip(path string) (bool, string) {
+	if ok, reason := isEnvFileReason(path); ok {
+		return true, reason
+	}
+	if ok, reason := isBinaryFileReason(path); ok {
+		return true, reason
+	}
+	if ok, reason := defaultSkipMatcher.Match(path); ok {
+		return true, reason
+	}
+	return false, ""
+}
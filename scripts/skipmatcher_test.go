@@ -0,0 +1,192 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeIgnoreFile writes a .securityhooksignore file with the given content
+// in dir, creating dir if needed.
+func writeIgnoreFile(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("failed to create dir %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ignoreFileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+}
+
+func Test_SkipMatcher_Match_Cases(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatalf("failed to create .git marker: %v", err)
+	}
+
+	writeIgnoreFile(t, root, "*.payload\nvendor/\n/scratch\n!vendor/keep.payload\n")
+
+	sub := filepath.Join(root, "nested")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatalf("failed to create nested dir: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "matches single-segment glob at root", path: filepath.Join(root, "secret.payload"), want: true},
+		{name: "single-segment glob also matches nested depth", path: filepath.Join(sub, "secret.payload"), want: true},
+		{name: "directory-only pattern matches the directory itself", path: filepath.Join(root, "vendor"), want: true},
+		{name: "directory-only pattern matches files beneath it", path: filepath.Join(root, "vendor", "lib.go"), want: true},
+		{name: "negated pattern un-ignores a specific file", path: filepath.Join(root, "vendor", "keep.payload"), want: false},
+		{name: "anchored pattern only matches at the ignore file's directory", path: filepath.Join(root, "scratch"), want: true},
+		{name: "anchored pattern does not match a same-named nested path", path: filepath.Join(sub, "scratch"), want: false},
+		{name: "unrelated file is not matched", path: filepath.Join(root, "main.go"), want: false},
+	}
+
+	matcher := NewSkipMatcher()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := matcher.Match(tt.path)
+			if got != tt.want {
+				t.Errorf("Match(%q) = %v (%q), want %v", tt.path, got, reason, tt.want)
+			}
+			if got && reason == "" {
+				t.Errorf("Match(%q) = true with empty reason", tt.path)
+			}
+		})
+	}
+}
+
+func Test_SkipMatcher_Match_NoIgnoreFile(t *testing.T) {
+	root := t.TempDir()
+	matcher := NewSkipMatcher()
+
+	got, reason := matcher.Match(filepath.Join(root, "anything.txt"))
+	if got {
+		t.Errorf("Match() = true (%q), want false when no .securityhooksignore exists", reason)
+	}
+}
+
+func Test_SkipMatcher_Match_CachesParsedRules(t *testing.T) {
+	root := t.TempDir()
+	writeIgnoreFile(t, root, "*.payload\n")
+
+	matcher := NewSkipMatcher()
+	path := filepath.Join(root, "a.payload")
+
+	if got, _ := matcher.Match(path); !got {
+		t.Fatalf("Match(%q) = false before cache warm, want true", path)
+	}
+
+	// Remove the ignore file; a cached matcher should keep using the
+	// rules it already parsed rather than re-reading the directory.
+	if err := os.Remove(filepath.Join(root, ignoreFileName)); err != nil {
+		t.Fatalf("failed to remove ignore file: %v", err)
+	}
+
+	if got, _ := matcher.Match(path); !got {
+		t.Errorf("Match(%q) = false after cache warm, want true (cache should not re-read)", path)
+	}
+}
+
+func Test_SkipMatcher_Match_BuiltinExcludedPaths(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatalf("failed to create .git marker: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "vendored dependency", path: filepath.Join(root, "vendor", "lib", "pkg.go"), want: true},
+		{name: "node_modules package", path: filepath.Join(root, "node_modules", "left-pad", "index.js"), want: true},
+		{name: "nested testdata fixture", path: filepath.Join(root, "pkg", "testdata", "fixture.txt"), want: true},
+		{name: "minified bundle at any depth", path: filepath.Join(root, "assets", "app.min.js"), want: true},
+		{name: "dist output", path: filepath.Join(root, "dist", "bundle.js"), want: true},
+		{name: "ordinary source file", path: filepath.Join(root, "pkg", "main.go"), want: false},
+	}
+
+	matcher := NewSkipMatcher()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := matcher.Match(tt.path)
+			if got != tt.want {
+				t.Errorf("Match(%q) = %v (%q), want %v", tt.path, got, reason, tt.want)
+			}
+			if got && !strings.Contains(reason, "excluded path") {
+				t.Errorf("Match(%q) reason = %q, want it to contain %q", tt.path, reason, "excluded path")
+			}
+		})
+	}
+}
+
+func Test_SkipMatcher_SetExcludedPaths(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatalf("failed to create .git marker: %v", err)
+	}
+
+	matcher := NewSkipMatcher()
+	matcher.SetExcludedPaths(compilePatternList([]string{"generated/", "!generated/keep.go"}, "test"))
+
+	excluded := filepath.Join(root, "generated", "drop.go")
+	if got, _ := matcher.Match(excluded); !got {
+		t.Errorf("Match(%q) = false, want true: excludedPaths entry should match", excluded)
+	}
+
+	kept := filepath.Join(root, "generated", "keep.go")
+	if got, _ := matcher.Match(kept); got {
+		t.Errorf("Match(%q) = true, want false: negated excludedPaths entry should re-include it", kept)
+	}
+}
+
+func Test_ShouldSkip_Cases(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantSkip   bool
+		wantReason string
+	}{
+		{name: "env file", path: ".env", wantSkip: true, wantReason: "env file"},
+		{name: "binary extension", path: "image.png", wantSkip: true, wantReason: "binary extension"},
+		{name: "regular source file", path: "main.go", wantSkip: false, wantReason: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSkip, gotReason := ShouldSkip(tt.path)
+			if gotSkip != tt.wantSkip {
+				t.Errorf("ShouldSkip(%q) skip = %v, want %v", tt.path, gotSkip, tt.wantSkip)
+			}
+			if gotReason != tt.wantReason {
+				t.Errorf("ShouldSkip(%q) reason = %q, want %q", tt.path, gotReason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func Test_ShouldSkip_UserPattern(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatalf("failed to create .git marker: %v", err)
+	}
+	writeIgnoreFile(t, root, "*.secretdata\n")
+
+	defaultSkipMatcher = NewSkipMatcher()
+	t.Cleanup(func() { defaultSkipMatcher = NewSkipMatcher() })
+
+	path := filepath.Join(root, "thing.secretdata")
+	gotSkip, gotReason := ShouldSkip(path)
+	if !gotSkip {
+		t.Fatalf("ShouldSkip(%q) = false, want true", path)
+	}
+	if !strings.Contains(gotReason, "user pattern") {
+		t.Errorf("ShouldSkip(%q) reason = %q, want it to contain %q", path, gotReason, "user pattern")
+	}
+}
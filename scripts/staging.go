@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// StagedReader abstracts access to staged (index) file content so the
+// scanner can read blobs directly from the object store instead of
+// shelling out to git for every file.
+type StagedReader interface {
+	// ListStagedFiles returns the paths currently staged in the index.
+	ListStagedFiles() ([]string, error)
+	// ReadStagedFile returns the staged (index) content of path.
+	ReadStagedFile(path string) (string, error)
+	// Close releases any resources (subprocesses, handles) held by the reader.
+	Close() error
+}
+
+// legacyStagedReader is the original implementation that shells out to git
+// once per file. It is the last-resort fallback when neither go-git nor a
+// CatFileBatch process can be used.
+type legacyStagedReader struct{}
+
+func (legacyStagedReader) ListStagedFiles() ([]string, error) {
+	return GetStagedFiles()
+}
+
+func (legacyStagedReader) ReadStagedFile(path string) (string, error) {
+	return GetStagedContent(path)
+}
+
+func (legacyStagedReader) Close() error { return nil }
+
+// catFileStagedReader lists staged files the usual way but reads their
+// content through a single long-lived "git cat-file --batch" process,
+// avoiding a fork/exec per file. CatFileBatch.Read is not safe for
+// concurrent use, so reads are serialized behind mu.
+type catFileStagedReader struct {
+	mu    sync.Mutex
+	batch *CatFileBatch
+}
+
+func (r *catFileStagedReader) ListStagedFiles() ([]string, error) {
+	return GetStagedFiles()
+}
+
+func (r *catFileStagedReader) ReadStagedFile(path string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.batch.Read(path)
+}
+
+func (r *catFileStagedReader) Close() error {
+	return r.batch.Close()
+}
+
+// goGitStagedReader reads staged blobs directly from the repository's
+// object store via go-git, avoiding a fork/exec per file.
+type goGitStagedReader struct {
+	repo *git.Repository
+}
+
+// NewStagedReader opens projectRoot with go-git and returns a StagedReader
+// backed by the object store. If the repository can't be opened this way
+// (partial checkout, sparse index, corrupt .git, etc.) it falls back to a
+// StagedReader backed by a single "git cat-file --batch" process, and
+// finally to one that shells out per file, so callers always get a usable
+// implementation.
+func NewStagedReader(projectRoot string) StagedReader {
+	if repo, err := git.PlainOpen(projectRoot); err == nil {
+		return &goGitStagedReader{repo: repo}
+	}
+
+	if batch, err := NewCatFileBatch(projectRoot); err == nil {
+		return &catFileStagedReader{batch: batch}
+	}
+
+	return legacyStagedReader{}
+}
+
+// headTree returns HEAD's tree, or nil if the repository has no commits
+// yet (a brand-new repo's first commit), in which case every index entry
+// counts as staged since there's nothing to compare it against.
+func (r *goGitStagedReader) headTree() (*object.Tree, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	return commit.Tree()
+}
+
+// ListStagedFiles returns only the index entries that actually differ from
+// HEAD - a new file, or one whose staged blob hash doesn't match HEAD's -
+// mirroring "git diff --cached --name-only" rather than every path the
+// index happens to track.
+func (r *goGitStagedReader) ListStagedFiles() ([]string, error) {
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	headTree, err := r.headTree()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(idx.Entries))
+	for _, entry := range idx.Entries {
+		if headTree == nil {
+			files = append(files, entry.Name)
+			continue
+		}
+
+		headFile, err := headTree.File(entry.Name)
+		if err != nil {
+			if errors.Is(err, object.ErrFileNotFound) {
+				files = append(files, entry.Name)
+				continue
+			}
+			return nil, err
+		}
+		if headFile.Hash != entry.Hash {
+			files = append(files, entry.Name)
+		}
+	}
+
+	return files, nil
+}
+
+func (r *goGitStagedReader) ReadStagedFile(path string) (string, error) {
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return "", err
+	}
+
+	entry, err := idx.Entry(path)
+	if err != nil {
+		return "", fmt.Errorf("path %q not staged: %w", path, err)
+	}
+
+	blob, err := r.repo.BlobObject(entry.Hash)
+	if err != nil {
+		return "", err
+	}
+
+	if blob.Size > MaxFileSize {
+		return "", fmt.Errorf("%w: %q is %d bytes", ErrBlobTooLarge, path, blob.Size)
+	}
+
+	reader, err := blob.Reader()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = reader.Close() }()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+func (r *goGitStagedReader) Close() error { return nil }
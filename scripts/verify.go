@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// verifyTimeout bounds a single live credential check. There are no
+// retries: a slow or down provider should fail closed to "unverified"
+// rather than stall the hook.
+const verifyTimeout = 2 * time.Second
+
+// VerifyResult is the outcome of a live credential check.
+type VerifyResult struct {
+	Verified bool
+	Meta     map[string]string
+}
+
+// Verifier confirms whether secret is a live, valid credential by probing
+// its provider's API.
+type Verifier interface {
+	Verify(ctx context.Context, secret string) (VerifyResult, error)
+}
+
+// verifiers maps a SecretPattern's RuleID to the Verifier that can confirm a
+// match for it live. Only rule IDs with a cheap, read-only identity endpoint
+// are registered here; an AWS access key and its paired secret key are
+// matched as two independent Findings with no way to reunite them, so
+// sts:GetCallerIdentity verification isn't wired up despite being mentioned
+// as a candidate - everything else is left unverified.
+var verifiers = map[string]Verifier{
+	"github-pat":        githubPATVerifier{},
+	"anthropic-api-key": anthropicAPIKeyVerifier{},
+}
+
+// verifyCacheMu and verifyCache memoize Verify results by secret hash for
+// the process lifetime, so a credential repeated across many staged files
+// or lines is only probed once.
+var (
+	verifyCacheMu sync.Mutex
+	verifyCache   = make(map[string]VerifyResult)
+)
+
+// VerifyEnabled reports whether live credential verification was opted into
+// via SECRET_VERIFY=1. It defaults off since it makes outbound network
+// calls with the matched secret.
+func VerifyEnabled() bool {
+	return os.Getenv("SECRET_VERIFY") == "1"
+}
+
+// VerifyStrict reports whether an unverified match against a registered
+// Verifier should be downgraded to a warning instead of blocking the tool
+// call, via SECRET_VERIFY_STRICT=1.
+func VerifyStrict() bool {
+	return os.Getenv("SECRET_VERIFY_STRICT") == "1"
+}
+
+// verifyMatch probes secret against ruleID's registered Verifier, if any,
+// caching the result by secret hash for the process lifetime. It reports
+// ok=false when no Verifier is registered for ruleID, in which case result
+// is the zero value and should be ignored. A probe error is treated the
+// same as an unverified result rather than propagated, since a network
+// hiccup shouldn't be reported as proof the credential is invalid.
+func verifyMatch(ruleID, secret string) (result VerifyResult, ok bool) {
+	v, ok := verifiers[ruleID]
+	if !ok {
+		return VerifyResult{}, false
+	}
+
+	key := SecretHash(secret)
+	verifyCacheMu.Lock()
+	cached, hit := verifyCache[key]
+	verifyCacheMu.Unlock()
+	if hit {
+		return cached, true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+
+	result, err := v.Verify(ctx, secret)
+	if err != nil {
+		result = VerifyResult{}
+	}
+
+	verifyCacheMu.Lock()
+	verifyCache[key] = result
+	verifyCacheMu.Unlock()
+
+	return result, true
+}
+
+// githubPATVerifier confirms a GitHub personal access token is live via a
+// GET against the authenticated-user endpoint. baseURL is overridable in
+// tests; it defaults to the real GitHub API.
+type githubPATVerifier struct {
+	baseURL string
+}
+
+func (v githubPATVerifier) apiBase() string {
+	if v.baseURL != "" {
+		return v.baseURL
+	}
+	return "https://api.github.com"
+}
+
+func (v githubPATVerifier) Verify(ctx context.Context, secret string) (VerifyResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.apiBase()+"/user", nil)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	req.Header.Set("Authorization", "token "+secret)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return VerifyResult{}, nil
+	}
+
+	var body struct {
+		Login string `json:"login"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+
+	return VerifyResult{Verified: true, Meta: map[string]string{"login": body.Login}}, nil
+}
+
+// anthropicAPIKeyVerifier confirms an Anthropic API key is live via a HEAD
+// request against the models endpoint. baseURL is overridable in tests; it
+// defaults to the real Anthropic API.
+type anthropicAPIKeyVerifier struct {
+	baseURL string
+}
+
+func (v anthropicAPIKeyVerifier) apiBase() string {
+	if v.baseURL != "" {
+		return v.baseURL
+	}
+	return "https://api.anthropic.com"
+}
+
+func (v anthropicAPIKeyVerifier) Verify(ctx context.Context, secret string) (VerifyResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, v.apiBase()+"/v1/models", nil)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	req.Header.Set("x-api-key", secret)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return VerifyResult{}, nil
+	}
+
+	return VerifyResult{Verified: true}, nil
+}
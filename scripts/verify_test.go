@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_GithubPATVerifier_Verify_Cases(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      http.HandlerFunc
+		wantVerified bool
+		wantLogin    string
+	}{
+		{
+			name: "authenticated user responds 200 with a login",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "token ghp_faketoken" {
+					t.Errorf("request Authorization header = %q, want %q", r.Header.Get("Authorization"), "token ghp_faketoken")
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"login":"octocat"}`))
+			},
+			wantVerified: true,
+			wantLogin:    "octocat",
+		},
+		{
+			name: "revoked token responds 401",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			wantVerified: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			v := githubPATVerifier{baseURL: server.URL}
+			result, err := v.Verify(context.Background(), "ghp_faketoken")
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if result.Verified != tt.wantVerified {
+				t.Errorf("Verify().Verified = %v, want %v", result.Verified, tt.wantVerified)
+			}
+			if tt.wantLogin != "" && result.Meta["login"] != tt.wantLogin {
+				t.Errorf("Verify().Meta[login] = %q, want %q", result.Meta["login"], tt.wantLogin)
+			}
+		})
+	}
+}
+
+func Test_AnthropicAPIKeyVerifier_Verify_Cases(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      http.HandlerFunc
+		wantVerified bool
+	}{
+		{
+			name: "live key responds 200",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodHead {
+					t.Errorf("request method = %q, want %q", r.Method, http.MethodHead)
+				}
+				if r.Header.Get("x-api-key") != "sk-ant-faketoken" {
+					t.Errorf("request x-api-key header = %q, want %q", r.Header.Get("x-api-key"), "sk-ant-faketoken")
+				}
+				w.WriteHeader(http.StatusOK)
+			},
+			wantVerified: true,
+		},
+		{
+			name: "revoked key responds 401",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			wantVerified: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			v := anthropicAPIKeyVerifier{baseURL: server.URL}
+			result, err := v.Verify(context.Background(), "sk-ant-faketoken")
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if result.Verified != tt.wantVerified {
+				t.Errorf("Verify().Verified = %v, want %v", result.Verified, tt.wantVerified)
+			}
+		})
+	}
+}
+
+// fakeVerifier is a stub Verifier for exercising verifyMatch's caching and
+// registry-lookup behavior without a real network call.
+type fakeVerifier struct {
+	calls  *int
+	result VerifyResult
+}
+
+func (f fakeVerifier) Verify(ctx context.Context, secret string) (VerifyResult, error) {
+	*f.calls++
+	return f.result, nil
+}
+
+func Test_VerifyMatch_UnregisteredRuleIDIsNotAttempted(t *testing.T) {
+	if _, ok := verifyMatch("generic-secret-token", "whatever"); ok {
+		t.Error("verifyMatch() ok = true for a rule ID with no registered Verifier, want false")
+	}
+}
+
+func Test_VerifyMatch_CachesBySecretHash(t *testing.T) {
+	origVerifiers := verifiers
+	origCache := verifyCache
+	defer func() {
+		verifiers = origVerifiers
+		verifyCache = origCache
+	}()
+
+	calls := 0
+	verifiers = map[string]Verifier{
+		"fake-rule": fakeVerifier{calls: &calls, result: VerifyResult{Verified: true, Meta: map[string]string{"k": "v"}}},
+	}
+	verifyCache = make(map[string]VerifyResult)
+
+	result, ok := verifyMatch("fake-rule", "some-secret")
+	if !ok || !result.Verified || result.Meta["k"] != "v" {
+		t.Fatalf("verifyMatch() = (%+v, %v), want Verified result", result, ok)
+	}
+
+	if _, ok := verifyMatch("fake-rule", "some-secret"); !ok {
+		t.Fatal("verifyMatch() ok = false on cached lookup, want true")
+	}
+
+	if calls != 1 {
+		t.Errorf("underlying Verifier called %d times, want 1 (second lookup should hit the cache)", calls)
+	}
+}
+
+func Test_VerifyEnabled_And_VerifyStrict_ReadEnvVars(t *testing.T) {
+	if VerifyEnabled() {
+		t.Error("VerifyEnabled() = true with SECRET_VERIFY unset, want false")
+	}
+	t.Setenv("SECRET_VERIFY", "1")
+	if !VerifyEnabled() {
+		t.Error("VerifyEnabled() = false with SECRET_VERIFY=1, want true")
+	}
+
+	if VerifyStrict() {
+		t.Error("VerifyStrict() = true with SECRET_VERIFY_STRICT unset, want false")
+	}
+	t.Setenv("SECRET_VERIFY_STRICT", "1")
+	if !VerifyStrict() {
+		t.Error("VerifyStrict() = false with SECRET_VERIFY_STRICT=1, want true")
+	}
+}